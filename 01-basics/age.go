@@ -0,0 +1,163 @@
+package basics
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrDateOfBirthRequired = errors.New("date of birth is required")
+	ErrDateOfBirthInFuture = errors.New("date of birth is in the future")
+	ErrImplausibleAge      = errors.New("age is implausible")
+	ErrUnderMinimumAge     = errors.New("user is below the minimum age")
+	ErrUnknownJurisdiction = errors.New("unknown jurisdiction")
+)
+
+// Errors for the common minimum ages. Each one also matches
+// ErrUnderMinimumAge with errors.Is.
+var (
+	ErrUnderAge13 = fmt.Errorf("%w of 13", ErrUnderMinimumAge)
+	ErrUnderAge16 = fmt.Errorf("%w of 16", ErrUnderMinimumAge)
+	ErrUnderAge18 = fmt.Errorf("%w of 18", ErrUnderMinimumAge)
+)
+
+// MaxPlausibleAge is the oldest age accepted by default.
+const MaxPlausibleAge = 150
+
+// Clock tells the current time. Inject a fixed clock in tests.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts a function to the Clock interface.
+type ClockFunc func() time.Time
+
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// SystemClock reads the system time.
+var SystemClock Clock = ClockFunc(time.Now)
+
+// AgeAt returns the age in whole years of someone born on dob, on the given
+// day. Only calendar dates are compared, in the location of on. Someone born
+// on 29 February becomes a year older on 1 March in non-leap years.
+func AgeAt(dob, on time.Time) int {
+	by, bm, bd := dob.Date()
+	y, m, d := on.Date()
+
+	age := y - by
+	birthday := time.Date(y, bm, bd, 0, 0, 0, 0, time.UTC)
+	// time.Date normalizes 29 February in a non-leap year to 1 March.
+	if m < birthday.Month() || (m == birthday.Month() && d < birthday.Day()) {
+		age--
+	}
+	return age
+}
+
+// AgePolicy validates a date of birth against a jurisdiction's rules. It is
+// a Rule[time.Time].
+type AgePolicy struct {
+	Jurisdiction string
+	// MinimumAge is the age a user must have reached. Zero disables the
+	// check.
+	MinimumAge int
+	// UnderAgeErr is returned for users younger than MinimumAge. Nil means
+	// ErrUnderMinimumAge.
+	UnderAgeErr error
+	// MaximumAge rejects implausibly old dates of birth. Zero means
+	// MaxPlausibleAge.
+	MaximumAge int
+	// Clock supplies today's date. Nil means SystemClock.
+	Clock Clock
+}
+
+// Common policies. The minimum ages follow COPPA in the United States and
+// the GDPR default for a child's consent to online services in the EU.
+var (
+	DefaultAgePolicy = AgePolicy{}
+	COPPAAgePolicy   = AgePolicy{Jurisdiction: "US", MinimumAge: 13, UnderAgeErr: ErrUnderAge13}
+	GDPRAgePolicy    = AgePolicy{Jurisdiction: "EU", MinimumAge: 16, UnderAgeErr: ErrUnderAge16}
+	AdultAgePolicy   = AgePolicy{MinimumAge: 18, UnderAgeErr: ErrUnderAge18}
+)
+
+// Validate checks dob: it must be set, not in the future, plausible, and
+// old enough for the policy.
+func (p AgePolicy) Validate(dob time.Time) error {
+	if dob.IsZero() {
+		return ErrDateOfBirthRequired
+	}
+
+	clock := p.Clock
+	if clock == nil {
+		clock = SystemClock
+	}
+	now := clock.Now()
+
+	y, m, d := dob.Date()
+	if time.Date(y, m, d, 0, 0, 0, 0, now.Location()).After(now) {
+		return ErrDateOfBirthInFuture
+	}
+
+	age := AgeAt(dob, now)
+	maxAge := p.MaximumAge
+	if maxAge == 0 {
+		maxAge = MaxPlausibleAge
+	}
+	if age > maxAge {
+		return &ParamError{
+			Err:     ErrImplausibleAge,
+			Params:  map[string]any{"max": maxAge},
+			Message: fmt.Sprintf("%v (maximum %d)", ErrImplausibleAge, maxAge),
+		}
+	}
+
+	if age < p.MinimumAge {
+		err := p.UnderAgeErr
+		if err == nil {
+			err = ErrUnderMinimumAge
+		}
+		return WithParams(err, map[string]any{"min": p.MinimumAge})
+	}
+	return nil
+}
+
+var (
+	agePoliciesMu sync.RWMutex
+	agePolicies   = map[string]AgePolicy{
+		"US": COPPAAgePolicy,
+		"GB": withJurisdiction(COPPAAgePolicy, "GB"),
+		"EU": GDPRAgePolicy,
+		"DE": withJurisdiction(GDPRAgePolicy, "DE"),
+		"IE": withJurisdiction(GDPRAgePolicy, "IE"),
+		"NL": withJurisdiction(GDPRAgePolicy, "NL"),
+	}
+)
+
+func withJurisdiction(p AgePolicy, jurisdiction string) AgePolicy {
+	p.Jurisdiction = jurisdiction
+	return p
+}
+
+// RegisterAgePolicy makes a policy available to LookupAgePolicy under its
+// jurisdiction code.
+func RegisterAgePolicy(p AgePolicy) {
+	agePoliciesMu.Lock()
+	defer agePoliciesMu.Unlock()
+	agePolicies[strings.ToUpper(p.Jurisdiction)] = p
+}
+
+// LookupAgePolicy returns the policy for a jurisdiction code such as "US"
+// or "DE".
+func LookupAgePolicy(jurisdiction string) (AgePolicy, error) {
+	agePoliciesMu.RLock()
+	defer agePoliciesMu.RUnlock()
+	p, ok := agePolicies[strings.ToUpper(jurisdiction)]
+	if !ok {
+		return AgePolicy{}, fmt.Errorf("%w: %q", ErrUnknownJurisdiction, jurisdiction)
+	}
+	return p, nil
+}
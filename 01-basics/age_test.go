@@ -0,0 +1,140 @@
+package basics
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func fixedClock(date string) Clock {
+	now := mustDate(date)
+	return ClockFunc(func() time.Time { return now })
+}
+
+func TestAgeAt(t *testing.T) {
+	tests := []struct {
+		dob, on string
+		want    int
+	}{
+		{"2000-06-15", "2024-06-14", 23},
+		{"2000-06-15", "2024-06-15", 24},
+		{"2000-06-15", "2024-12-31", 24},
+		{"2000-01-01", "2000-01-01", 0},
+		{"2000-02-29", "2023-02-28", 22},
+		{"2000-02-29", "2023-03-01", 23},
+		{"2000-02-29", "2024-02-28", 23},
+		{"2000-02-29", "2024-02-29", 24},
+		{"1999-12-31", "2000-01-01", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dob+" on "+tt.on, func(t *testing.T) {
+			if got := AgeAt(mustDate(tt.dob), mustDate(tt.on)); got != tt.want {
+				t.Errorf("AgeAt(%s, %s) = %d; want %d", tt.dob, tt.on, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAgePolicyValidate(t *testing.T) {
+	clock := fixedClock("2024-05-10")
+	withClock := func(p AgePolicy) AgePolicy {
+		p.Clock = clock
+		return p
+	}
+
+	tests := []struct {
+		name    string
+		policy  AgePolicy
+		dob     time.Time
+		wantErr error
+	}{
+		{"no minimum", withClock(DefaultAgePolicy), mustDate("2020-01-01"), nil},
+		{"born today", withClock(DefaultAgePolicy), mustDate("2024-05-10"), nil},
+		{"missing", withClock(DefaultAgePolicy), time.Time{}, ErrDateOfBirthRequired},
+		{"future", withClock(DefaultAgePolicy), mustDate("2024-05-11"), ErrDateOfBirthInFuture},
+		{"implausible", withClock(DefaultAgePolicy), mustDate("1870-01-01"), ErrImplausibleAge},
+		{"custom maximum", withClock(AgePolicy{MaximumAge: 120}), mustDate("1900-01-01"), ErrImplausibleAge},
+		{"COPPA 13th birthday", withClock(COPPAAgePolicy), mustDate("2011-05-10"), nil},
+		{"COPPA day before", withClock(COPPAAgePolicy), mustDate("2011-05-11"), ErrUnderAge13},
+		{"GDPR under 16", withClock(GDPRAgePolicy), mustDate("2010-01-01"), ErrUnderAge16},
+		{"GDPR 16", withClock(GDPRAgePolicy), mustDate("2008-05-10"), nil},
+		{"adult under 18", withClock(AdultAgePolicy), mustDate("2007-01-01"), ErrUnderAge18},
+		{"custom minimum", withClock(AgePolicy{MinimumAge: 21}), mustDate("2004-01-01"), ErrUnderMinimumAge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate(tt.dob)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate(%s) error = %v; want %v", tt.dob.Format(time.DateOnly), err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUnderAgeErrorsAreDistinct(t *testing.T) {
+	clock := fixedClock("2024-05-10")
+	dob := mustDate("2012-01-01") // 12 years old
+
+	var codes []string
+	for _, p := range []AgePolicy{COPPAAgePolicy, GDPRAgePolicy, AdultAgePolicy} {
+		p.Clock = clock
+		err := p.Validate(dob)
+		if !errors.Is(err, ErrUnderMinimumAge) {
+			t.Errorf("%d: error = %v; want it to match ErrUnderMinimumAge", p.MinimumAge, err)
+		}
+		fe := NewFieldError("DateOfBirth", dob, err)
+		if fe.Params["min"] != p.MinimumAge {
+			t.Errorf("%d: params = %v", p.MinimumAge, fe.Params)
+		}
+		codes = append(codes, fe.Code)
+	}
+
+	want := []string{"age.under_13", "age.under_16", "age.under_18"}
+	for i := range want {
+		if codes[i] != want[i] {
+			t.Errorf("codes = %v; want %v", codes, want)
+			break
+		}
+	}
+	if errors.Is(ErrUnderAge13, ErrUnderAge16) {
+		t.Error("ErrUnderAge13 matches ErrUnderAge16")
+	}
+}
+
+func TestLookupAgePolicy(t *testing.T) {
+	p, err := LookupAgePolicy("de")
+	if err != nil || p.MinimumAge != 16 || p.Jurisdiction != "DE" {
+		t.Errorf("LookupAgePolicy(de) = %+v, %v; want DE with minimum 16", p, err)
+	}
+	if _, err := LookupAgePolicy("XX"); !errors.Is(err, ErrUnknownJurisdiction) {
+		t.Errorf("LookupAgePolicy(XX) error = %v; want ErrUnknownJurisdiction", err)
+	}
+
+	RegisterAgePolicy(AgePolicy{Jurisdiction: "kr", MinimumAge: 14})
+	if p, err := LookupAgePolicy("KR"); err != nil || p.MinimumAge != 14 {
+		t.Errorf("LookupAgePolicy(KR) = %+v, %v; want minimum 14", p, err)
+	}
+}
+
+func TestUserDateOfBirth(t *testing.T) {
+	u := User{Username: "alice", Email: "alice@example.com", Age: 30, DateOfBirth: mustDate("1990-02-01")}
+	if got := u.CurrentAge(fixedClock("2024-01-31")); got != 33 {
+		t.Errorf("CurrentAge = %d; want 33", got)
+	}
+	if got := (User{Age: 30}).CurrentAge(SystemClock); got != 30 {
+		t.Errorf("CurrentAge without date of birth = %d; want 30", got)
+	}
+
+	if err := ValidateUser(u); err != nil {
+		t.Errorf("ValidateUser returned error: %v", err)
+	}
+
+	u.DateOfBirth = time.Now().AddDate(1, 0, 0)
+	err := ValidateUser(u)
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Path != "DateOfBirth" || !errors.Is(err, ErrDateOfBirthInFuture) {
+		t.Errorf("ValidateUser(future date of birth) error = %v; want DateOfBirth in the future", err)
+	}
+}
@@ -0,0 +1,126 @@
+package basics
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ContextRule is a rule that may do I/O, such as a DNS lookup. It should
+// give up when ctx is done.
+type ContextRule[T any] interface {
+	ValidateContext(ctx context.Context, value T) error
+}
+
+// ContextRuleFunc adapts an ordinary function to the ContextRule interface.
+type ContextRuleFunc[T any] func(ctx context.Context, value T) error
+
+func (f ContextRuleFunc[T]) ValidateContext(ctx context.Context, value T) error {
+	return f(ctx, value)
+}
+
+type fieldContextRule[T, F any] struct {
+	name  string
+	get   func(T) F
+	rules []ContextRule[F]
+}
+
+// ValidateContext runs the field's rules in order and stops at the first
+// failure, which is attributed to the field as by Field.
+func (r fieldContextRule[T, F]) ValidateContext(ctx context.Context, value T) error {
+	field := r.get(value)
+	for _, rule := range r.rules {
+		if err := rule.ValidateContext(ctx, field); err != nil {
+			return fieldError(r.name, field, err)
+		}
+	}
+	return nil
+}
+
+func (r fieldContextRule[T, F]) fieldName() string {
+	return r.name
+}
+
+// FieldContext is the ContextRule counterpart of Field. An AsyncValidator
+// skips it when the field has already failed a cheap rule.
+func FieldContext[T, F any](name string, get func(T) F, rules ...ContextRule[F]) ContextRule[T] {
+	return fieldContextRule[T, F]{name: name, get: get, rules: rules}
+}
+
+// AsyncValidator combines cheap rules with rules that do I/O. Validate runs
+// the cheap rules first, then runs the I/O rules concurrently, skipping
+// those for fields that already failed. All failures are reported together
+// as a ValidationErrors, cheap ones first.
+type AsyncValidator[T any] struct {
+	cheap *Validator[T]
+	rules []ContextRule[T]
+	// Timeout bounds the I/O phase. Zero means no limit beyond the
+	// caller's context.
+	Timeout time.Duration
+}
+
+// NewAsyncValidator returns a validator running cheap, which may be nil,
+// before rules.
+func NewAsyncValidator[T any](cheap *Validator[T], rules ...ContextRule[T]) *AsyncValidator[T] {
+	if cheap == nil {
+		cheap = NewValidator[T]()
+	}
+	return &AsyncValidator[T]{cheap: cheap, rules: rules}
+}
+
+// Add appends I/O rules and returns the validator for chaining.
+func (v *AsyncValidator[T]) Add(rules ...ContextRule[T]) *AsyncValidator[T] {
+	v.rules = append(v.rules, rules...)
+	return v
+}
+
+// ValidateContext returns nil or a ValidationErrors listing each failure.
+// Rules still running when ctx is done report their own errors, typically
+// wrapping ctx.Err().
+func (v *AsyncValidator[T]) ValidateContext(ctx context.Context, value T) error {
+	var errs ValidationErrors
+	if err := v.cheap.Validate(value); err != nil {
+		errs = appendErrors(errs, err)
+	}
+
+	if v.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, v.Timeout)
+		defer cancel()
+	}
+
+	results := make([]error, len(v.rules))
+	var wg sync.WaitGroup
+	for i, rule := range v.rules {
+		if named, ok := rule.(interface{ fieldName() string }); ok && hasFieldErrors(errs, named.fieldName()) {
+			continue
+		}
+		wg.Go(func() {
+			results[i] = rule.ValidateContext(ctx, value)
+		})
+	}
+	wg.Wait()
+
+	// Merge in rule order so that the result does not depend on timing.
+	for _, err := range results {
+		if err != nil {
+			errs = appendErrors(errs, err)
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// hasFieldErrors reports whether errs has an error for path or anything
+// nested inside it.
+func hasFieldErrors(errs ValidationErrors, path string) bool {
+	for _, e := range errs {
+		if e.Path == path || strings.HasPrefix(e.Path, path+".") || strings.HasPrefix(e.Path, path+"[") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package basics
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAsyncValidatorRunsCheapRulesFirst(t *testing.T) {
+	resolver := NewMockResolver().AddMX("example.com", "mx.example.com.")
+	v := UserAsyncValidator(resolver)
+
+	tests := []struct {
+		name     string
+		user     User
+		wantErrs []error
+		wantMX   int
+	}{
+		{"valid", User{Username: "johndoe", Email: "john@example.com", Age: 30}, nil, 1},
+		{"undeliverable", User{Username: "johndoe", Email: "john@nowhere.example", Age: 30}, []error{ErrEmailUndeliverable}, 1},
+		{"invalid email skips lookup", User{Username: "johndoe", Email: "john@", Age: 30}, []error{ErrInvalidEmail}, 0},
+		{"other field errors merge", User{Username: "", Email: "john@nowhere.example", Age: -1},
+			[]error{ErrEmptyUsername, ErrInvalidAge, ErrEmailUndeliverable}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := len(resolver.LookupMXCalls)
+			err := v.ValidateContext(context.Background(), tt.user)
+
+			var errs ValidationErrors
+			if len(tt.wantErrs) == 0 {
+				if err != nil {
+					t.Fatalf("ValidateContext error = %v; want nil", err)
+				}
+			} else if !errors.As(err, &errs) || len(errs) != len(tt.wantErrs) {
+				t.Fatalf("ValidateContext error = %v; want %d errors", err, len(tt.wantErrs))
+			}
+			for i, want := range tt.wantErrs {
+				if !errors.Is(errs[i], want) {
+					t.Errorf("errs[%d] = %v; want %v", i, errs[i], want)
+				}
+			}
+			if calls := len(resolver.LookupMXCalls) - before; calls != tt.wantMX {
+				t.Errorf("LookupMX calls = %d; want %d", calls, tt.wantMX)
+			}
+		})
+	}
+}
+
+func TestAsyncValidatorRunsRulesConcurrently(t *testing.T) {
+	var running, peak atomic.Int32
+	slow := ContextRuleFunc[string](func(ctx context.Context, _ string) error {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		select {
+		case <-time.After(50 * time.Millisecond):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	get := func(s string) string { return s }
+
+	v := NewAsyncValidator[string](nil,
+		FieldContext("A", get, ContextRule[string](slow)),
+		FieldContext("B", get, ContextRule[string](slow)),
+		FieldContext("C", get, ContextRule[string](slow)),
+	)
+	if err := v.ValidateContext(context.Background(), "x"); err != nil {
+		t.Fatalf("ValidateContext error = %v; want nil", err)
+	}
+	if peak.Load() != 3 {
+		t.Errorf("peak concurrency = %d; want 3", peak.Load())
+	}
+}
+
+func TestAsyncValidatorTimeout(t *testing.T) {
+	slow := NewMockResolver().AddMX("example.com", "mx.example.com.")
+	slow.Delay = time.Second
+
+	v := UserAsyncValidator(slow)
+	v.Timeout = 20 * time.Millisecond
+	err := v.ValidateContext(context.Background(), User{Username: "johndoe", Email: "john@example.com", Age: 30})
+	if !errors.Is(err, ErrEmailDomainUnverified) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v; want ErrEmailDomainUnverified from the deadline", err)
+	}
+
+	var errs ValidationErrors
+	if errors.As(err, &errs) && errs[0].Path != "Email" {
+		t.Errorf("Path = %q; want Email", errs[0].Path)
+	}
+}
+
+func TestAsyncValidatorCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	v := NewAsyncValidator[int](NewValidator(Field("N", func(n int) int { return n }, Between(0, 10, ErrTooLarge))),
+		ContextRuleFunc[int](func(ctx context.Context, _ int) error { return ctx.Err() }))
+	err := v.ValidateContext(ctx, 20)
+	if !errors.Is(err, ErrTooLarge) || !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v; want both the cheap failure and context.Canceled", err)
+	}
+}
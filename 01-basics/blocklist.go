@@ -0,0 +1,390 @@
+package basics
+
+import (
+	"bufio"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+var ErrUsernameBlocked = errors.New("username contains a blocked term")
+
+var (
+	//go:embed data/blocklist.txt
+	defaultBlockedTerms string
+	//go:embed data/allowlist.txt
+	defaultAllowedTerms string
+)
+
+// DefaultBlocklist holds the built-in impersonation and profanity terms. It
+// is used by DefaultUsernamePolicy.
+var DefaultBlocklist = func() *Blocklist {
+	b := NewBlocklist()
+	// The embedded files are known to be readable.
+	_ = b.LoadBlocked(strings.NewReader(defaultBlockedTerms))
+	_ = b.LoadAllowed(strings.NewReader(defaultAllowedTerms))
+	return b
+}()
+
+// Blocklist rejects usernames containing blocked terms, ignoring case,
+// separators and leetspeak ("4dm1n" and "a.d.m.i.n" both contain "admin").
+//
+// A term matches whole words only, so "staff" rejects "staff", "the_staff"
+// and "StaffTeam" but not "staffan". Words are split at separators, where a
+// lowercase letter is followed by an uppercase one, and before trailing
+// digits. A "*" at the start or end of a term lets it start or end inside a
+// word: "shit*" rejects "shitty" but not "yamashita", and "*admin*" matches
+// anywhere. A blocked match lying entirely inside an allowed term is
+// ignored, so allowing "scunthorpe" stops "*cunt*" from rejecting it.
+//
+// All terms are matched in a single pass with an Aho-Corasick automaton,
+// so the cost does not grow with the number of terms.
+type Blocklist struct {
+	mu      sync.RWMutex
+	blocked []blockedTerm
+	allowed []string
+	// Automata are rebuilt lazily after terms change.
+	blockMatcher *ahoCorasick
+	allowMatcher *ahoCorasick
+}
+
+func NewBlocklist(terms ...string) *Blocklist {
+	b := &Blocklist{}
+	b.Block(terms...)
+	return b
+}
+
+// blockedTerm is a blocked term with its "*" markers removed.
+type blockedTerm struct {
+	text string
+	// partialStart and partialEnd allow the term to start or end inside
+	// a word.
+	partialStart, partialEnd bool
+}
+
+func parseBlockedTerm(term string) blockedTerm {
+	var t blockedTerm
+	t.text, t.partialStart = strings.CutPrefix(term, "*")
+	t.text, t.partialEnd = strings.CutSuffix(t.text, "*")
+	return t
+}
+
+// Block adds terms to the blocklist.
+func (b *Blocklist) Block(terms ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, term := range terms {
+		b.blocked = append(b.blocked, parseBlockedTerm(term))
+	}
+	b.blockMatcher = nil
+}
+
+// Allow adds terms that override blocked matches inside them.
+func (b *Blocklist) Allow(terms ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.allowed = append(b.allowed, terms...)
+	b.allowMatcher = nil
+}
+
+// LoadBlocked reads blocked terms, one per line. Blank lines and lines
+// starting with "#" are ignored.
+func (b *Blocklist) LoadBlocked(r io.Reader) error {
+	terms, err := readTerms(r)
+	if err != nil {
+		return err
+	}
+	b.Block(terms...)
+	return nil
+}
+
+// LoadAllowed reads allowed terms in the same format as LoadBlocked.
+func (b *Blocklist) LoadAllowed(r io.Reader) error {
+	terms, err := readTerms(r)
+	if err != nil {
+		return err
+	}
+	b.Allow(terms...)
+	return nil
+}
+
+// LoadBlocklistFiles builds a blocklist from a file of blocked terms and an
+// optional file of allowed terms.
+func LoadBlocklistFiles(blockedPath, allowedPath string) (*Blocklist, error) {
+	b := NewBlocklist()
+	if err := loadTermsFile(blockedPath, b.LoadBlocked); err != nil {
+		return nil, err
+	}
+	if allowedPath != "" {
+		if err := loadTermsFile(allowedPath, b.LoadAllowed); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func loadTermsFile(path string, load func(io.Reader) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := load(f); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+func readTerms(r io.Reader) ([]string, error) {
+	var terms []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			terms = append(terms, line)
+		}
+	}
+	return terms, scanner.Err()
+}
+
+// Match returns the first blocked term found in username, without its "*"
+// markers.
+func (b *Blocklist) Match(username string) (string, bool) {
+	blockMatcher, allowMatcher := b.matchers()
+	text, bounds := foldBlocklistWords(username)
+
+	allowed := allowMatcher.findAll(text)
+	for _, m := range blockMatcher.findAll(text) {
+		term := b.term(m.pattern)
+		if !term.partialStart && !bounds[m.start] || !term.partialEnd && !bounds[m.end] {
+			continue
+		}
+		covered := false
+		for _, a := range allowed {
+			if a.start <= m.start && m.end <= a.end {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return term.text, true
+		}
+	}
+	return "", false
+}
+
+func (b *Blocklist) term(i int) blockedTerm {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.blocked[i]
+}
+
+// Validate fails with ErrUsernameBlocked if username contains a blocked
+// term.
+func (b *Blocklist) Validate(username string) error {
+	term, ok := b.Match(username)
+	if !ok {
+		return nil
+	}
+	return &ParamError{
+		Err:     ErrUsernameBlocked,
+		Params:  map[string]any{"term": term},
+		Message: fmt.Sprintf("%v %q", ErrUsernameBlocked, term),
+	}
+}
+
+func (b *Blocklist) matchers() (*ahoCorasick, *ahoCorasick) {
+	b.mu.RLock()
+	blockMatcher, allowMatcher := b.blockMatcher, b.allowMatcher
+	b.mu.RUnlock()
+	if blockMatcher != nil && allowMatcher != nil {
+		return blockMatcher, allowMatcher
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.blockMatcher == nil {
+		terms := make([]string, len(b.blocked))
+		for i, term := range b.blocked {
+			terms[i] = term.text
+		}
+		b.blockMatcher = newAhoCorasick(terms)
+	}
+	if b.allowMatcher == nil {
+		b.allowMatcher = newAhoCorasick(b.allowed)
+	}
+	return b.blockMatcher, b.allowMatcher
+}
+
+// leetspeak maps look-alike digits and symbols to the letter they usually
+// stand for. Since "1" and "|" can mean either "i" or "l", those letters
+// are merged too.
+var leetspeak = map[rune]rune{
+	'4': 'a', '@': 'a',
+	'8': 'b',
+	'3': 'e',
+	'6': 'g', '9': 'g',
+	'1': 'i', '!': 'i', '|': 'i', 'l': 'i',
+	'0': 'o',
+	'5': 's', '$': 's',
+	'7': 't', '+': 't',
+	'2': 'z',
+}
+
+// foldBlocklistText normalizes s, undoes leetspeak and drops everything but
+// letters, so that terms and usernames are compared on the same footing.
+func foldBlocklistText(s string) []rune {
+	var out []rune
+	for _, r := range NormalizeUsername(s) {
+		if mapped, ok := leetspeak[r]; ok {
+			r = mapped
+		}
+		if unicode.IsLetter(r) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// foldBlocklistWords folds s like foldBlocklistText, word by word. bounds[i]
+// reports whether a word starts or ends at text[i], with bounds[len(text)]
+// true.
+func foldBlocklistWords(s string) (text []rune, bounds []bool) {
+	bounds = []bool{true}
+	for _, word := range blocklistWords(s) {
+		folded := foldBlocklistText(word)
+		if len(folded) == 0 {
+			continue
+		}
+		text = append(text, folded...)
+		bounds = append(bounds, make([]bool, len(folded))...)
+		bounds[len(text)] = true
+	}
+	return text, bounds
+}
+
+// blocklistWords splits s at separators, where a lowercase letter is
+// followed by an uppercase one, and before trailing digits, so that
+// "the_StaffTeam42" gives "the", "Staff", "Team" and "42". Leetspeak symbols
+// such as "@" belong to words.
+func blocklistWords(s string) []string {
+	var words []string
+	appendWord := func(word string) {
+		if letters := strings.TrimRightFunc(word, unicode.IsDigit); letters != "" && letters != word {
+			words = append(words, letters, word[len(letters):])
+		} else {
+			words = append(words, word)
+		}
+	}
+	for _, field := range strings.FieldsFunc(s, isBlocklistSeparator) {
+		runes := []rune(field)
+		start := 0
+		for i := 1; i < len(runes); i++ {
+			if unicode.IsLower(runes[i-1]) && unicode.IsUpper(runes[i]) {
+				appendWord(string(runes[start:i]))
+				start = i
+			}
+		}
+		appendWord(string(runes[start:]))
+	}
+	return words
+}
+
+func isBlocklistSeparator(r rune) bool {
+	if _, ok := leetspeak[r]; ok {
+		return false
+	}
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.Is(unicode.M, r)
+}
+
+// ahoCorasick finds every occurrence of a fixed set of patterns in one pass
+// over the text.
+type ahoCorasick struct {
+	terms   []string
+	lengths []int // folded length of each term
+	nodes   []acNode
+}
+
+type acNode struct {
+	next map[rune]int
+	fail int
+	// out lists the patterns ending at this node, including those reached
+	// through fail links.
+	out []int
+}
+
+type acMatch struct {
+	start, end int
+	pattern    int
+}
+
+func newAhoCorasick(terms []string) *ahoCorasick {
+	m := &ahoCorasick{terms: terms, lengths: make([]int, len(terms)), nodes: []acNode{{next: map[rune]int{}}}}
+	for i, term := range terms {
+		node := 0
+		pattern := foldBlocklistText(term)
+		m.lengths[i] = len(pattern)
+		if len(pattern) == 0 {
+			continue
+		}
+		for _, r := range pattern {
+			child, ok := m.nodes[node].next[r]
+			if !ok {
+				child = len(m.nodes)
+				m.nodes = append(m.nodes, acNode{next: map[rune]int{}})
+				m.nodes[node].next[r] = child
+			}
+			node = child
+		}
+		m.nodes[node].out = append(m.nodes[node].out, i)
+	}
+
+	// Breadth-first, so that a node's fail target is complete before the
+	// node's children are processed.
+	queue := []int{}
+	for _, child := range m.nodes[0].next {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for r, child := range m.nodes[node].next {
+			fail := m.nodes[node].fail
+			for fail != 0 && !m.hasEdge(fail, r) {
+				fail = m.nodes[fail].fail
+			}
+			if target, ok := m.nodes[fail].next[r]; ok && target != child {
+				m.nodes[child].fail = target
+			}
+			m.nodes[child].out = append(m.nodes[child].out, m.nodes[m.nodes[child].fail].out...)
+			queue = append(queue, child)
+		}
+	}
+	return m
+}
+
+func (m *ahoCorasick) hasEdge(node int, r rune) bool {
+	_, ok := m.nodes[node].next[r]
+	return ok
+}
+
+// findAll returns matches ordered by end position.
+func (m *ahoCorasick) findAll(text []rune) []acMatch {
+	var matches []acMatch
+	node := 0
+	for i, r := range text {
+		for node != 0 && !m.hasEdge(node, r) {
+			node = m.nodes[node].fail
+		}
+		node = m.nodes[node].next[r] // 0 (the root) if there is no edge
+		for _, p := range m.nodes[node].out {
+			matches = append(matches, acMatch{start: i + 1 - m.lengths[p], end: i + 1, pattern: p})
+		}
+	}
+	return matches
+}
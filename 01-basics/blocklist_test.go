@@ -0,0 +1,153 @@
+package basics
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBlocklistMatch(t *testing.T) {
+	b := NewBlocklist("*admin*", "moderator", "*cunt*", "staff", "shit*")
+	b.Allow("scunthorpe", "badminton")
+
+	tests := []struct {
+		username string
+		term     string
+	}{
+		{"admin", "admin"},
+		{"superadmin42", "admin"},
+		{"4dm1n", "admin"},
+		{"@DM!N", "admin"},
+		{"a.d.m.i.n", "admin"},
+		{"ＡＤＭＩＮ", "admin"},
+		{"m0d3r4t0r", "moderator"},
+		{"scunthorpe_fan", ""},
+		{"badminton_club", ""},
+		{"badminton_admin", "admin"},
+		{"cunt_scunthorpe", "cunt"},
+		{"alice", ""},
+		{"adm", ""},
+		{"staff", "staff"},
+		{"the_st4ff", "staff"},
+		{"StaffTeam", "staff"},
+		{"staff42", "staff"},
+		{"s.t.a.f.f", "staff"},
+		{"staffan", ""},
+		{"chiefstaff", ""},
+		{"shitty", "shit"},
+		{"yamashita", ""},
+		{"kinoshita", ""},
+		{"ashitaka", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.username, func(t *testing.T) {
+			term, found := b.Match(tt.username)
+			if term != tt.term || found != (tt.term != "") {
+				t.Errorf("Match(%q) = %q, %v; want %q", tt.username, term, found, tt.term)
+			}
+		})
+	}
+}
+
+func TestBlocklistValidate(t *testing.T) {
+	b := NewBlocklist("staff")
+
+	err := b.Validate("st4ff_member")
+	if !errors.Is(err, ErrUsernameBlocked) {
+		t.Fatalf("Validate error = %v; want ErrUsernameBlocked", err)
+	}
+	fe := NewFieldError("Username", "st4ff_member", err)
+	if fe.Code != "username.blocked" || fe.Params["term"] != "staff" {
+		t.Errorf("FieldError = {%s %v}; want username.blocked with term staff", fe.Code, fe.Params)
+	}
+
+	b.Allow("staffordshire")
+	if err := b.Validate("staffordshire_bull"); err != nil {
+		t.Errorf("Validate after Allow returned error: %v", err)
+	}
+	b.Block("bull")
+	if err := b.Validate("staffordshire_bull"); !errors.Is(err, ErrUsernameBlocked) {
+		t.Errorf("Validate after Block error = %v; want ErrUsernameBlocked", err)
+	}
+}
+
+func TestAhoCorasickOverlappingPatterns(t *testing.T) {
+	m := newAhoCorasick([]string{"he", "she", "his", "hers"})
+	matches := m.findAll([]rune("ushers"))
+
+	var got []string
+	for _, match := range matches {
+		got = append(got, m.terms[match.pattern])
+	}
+	if strings.Join(got, ",") != "she,he,hers" {
+		t.Errorf("findAll(ushers) = %v; want [she he hers]", got)
+	}
+	if matches[0].start != 1 || matches[0].end != 4 {
+		t.Errorf("first match span = [%d,%d); want [1,4)", matches[0].start, matches[0].end)
+	}
+}
+
+func TestLoadBlocklistFiles(t *testing.T) {
+	dir := t.TempDir()
+	blocked := filepath.Join(dir, "blocked.txt")
+	allowed := filepath.Join(dir, "allowed.txt")
+	if err := os.WriteFile(blocked, []byte("# impersonation\nceo\n\nfounder\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(allowed, []byte("riceoil\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := LoadBlocklistFiles(blocked, allowed)
+	if err != nil {
+		t.Fatalf("LoadBlocklistFiles returned error: %v", err)
+	}
+	if _, found := b.Match("the_c3o"); !found {
+		t.Error("Match(the_c3o) = false; want true")
+	}
+	if _, found := b.Match("riceoil"); found {
+		t.Error("Match(riceoil) = true; want the allowlist to win")
+	}
+
+	if _, err := LoadBlocklistFiles(filepath.Join(dir, "missing.txt"), ""); err == nil {
+		t.Error("LoadBlocklistFiles(missing) returned nil error")
+	}
+}
+
+func TestValidateUsernameUsesDefaultBlocklist(t *testing.T) {
+	tests := []struct {
+		username string
+		wantErr  error
+	}{
+		{"the_4dm1n", ErrUsernameBlocked},
+		{"official_bank", ErrUsernameBlocked},
+		{"scunthorpe", nil},
+		{"badminton99", nil},
+		{"deepanshu_mehra", nil},
+		{"yamashita", nil},
+		{"kinoshita", nil},
+		{"ashitaka", nil},
+		{"nazir", nil},
+		{"staffan", nil},
+		{"cybersecurity", nil},
+		{"staff", ErrUsernameBlocked},
+		{"security_team", ErrUsernameBlocked},
+		{"nazi", ErrUsernameBlocked},
+		{"superadmin", ErrUsernameBlocked},
+	}
+
+	for _, tt := range tests {
+		if err := ValidateUsername(tt.username); !errors.Is(err, tt.wantErr) {
+			t.Errorf("ValidateUsername(%q) error = %v; want %v", tt.username, err, tt.wantErr)
+		}
+	}
+
+	policy := DefaultUsernamePolicy
+	policy.Blocklist = nil
+	if err := policy.Validate("the_4dm1n"); err != nil {
+		t.Errorf("Validate without blocklist returned error: %v", err)
+	}
+}
@@ -0,0 +1,371 @@
+package basics
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrImportFormat       = errors.New("malformed import file")
+	ErrImportInvalidValue = errors.New("value could not be read")
+	ErrDuplicateUsername  = errors.New("username appears earlier in the file")
+	ErrDuplicateEmail     = errors.New("email address appears earlier in the file")
+)
+
+// ImportFormat selects how BulkValidator reads records.
+type ImportFormat int
+
+const (
+	// ImportCSV reads a header row naming the columns username, email,
+	// age, date_of_birth, password and phone, in any order. Only username
+	// and email are required; unknown columns are ignored.
+	ImportCSV ImportFormat = iota
+	// ImportJSON reads either an array of objects or newline-delimited
+	// objects, with the same keys as the CSV columns.
+	ImportJSON
+)
+
+// ImportRow is one validated record.
+type ImportRow struct {
+	// Line is where the record starts in the file, counting from 1.
+	Line   int
+	User   User
+	Errors ValidationErrors
+}
+
+func (r ImportRow) Valid() bool {
+	return len(r.Errors) == 0
+}
+
+// ImportSummary counts the outcome of an import.
+type ImportSummary struct {
+	Rows               int `json:"rows"`
+	Valid              int `json:"valid"`
+	Invalid            int `json:"invalid"`
+	DuplicateUsernames int `json:"duplicate_usernames"`
+	DuplicateEmails    int `json:"duplicate_emails"`
+}
+
+// BulkValidator validates exported user records one at a time, so files of
+// any size can be checked. Only the keys used for duplicate detection are
+// kept in memory.
+type BulkValidator struct {
+	// Validator checks each user. Nil means DefaultUserValidator.
+	Validator *Validator[User]
+}
+
+// Validate reads records from r and calls handle with each one, valid or
+// not, in file order. A record repeating the sanitized username or
+// canonical email address of an earlier record is reported as a duplicate.
+// Validate stops early if handle returns an error or the file cannot be
+// read; errors in individual records are reported in their rows instead.
+func (b BulkValidator) Validate(r io.Reader, format ImportFormat, handle func(ImportRow) error) (ImportSummary, error) {
+	validator := b.Validator
+	if validator == nil {
+		validator = DefaultUserValidator()
+	}
+
+	var summary ImportSummary
+	usernames := make(map[string]int)
+	emails := make(map[string]int)
+
+	process := func(line int, rec importRecord) error {
+		row := ImportRow{Line: line}
+		row.User, row.Errors = rec.user()
+		if err := validator.Validate(row.User); err != nil {
+			for _, fe := range appendErrors(nil, err) {
+				if len(row.Errors.ForField(fe.Path)) == 0 {
+					row.Errors = append(row.Errors, fe)
+				}
+			}
+		}
+
+		if key := SanitizeUsername(row.User.Username); key != "" {
+			if first, ok := usernames[key]; ok {
+				row.Errors = append(row.Errors, duplicateError("Username", row.User.Username, ErrDuplicateUsername, first))
+				summary.DuplicateUsernames++
+			} else {
+				usernames[key] = line
+			}
+		}
+		if key := importEmailKey(row.User.Email); key != "" {
+			if first, ok := emails[key]; ok {
+				row.Errors = append(row.Errors, duplicateError("Email", row.User.Email, ErrDuplicateEmail, first))
+				summary.DuplicateEmails++
+			} else {
+				emails[key] = line
+			}
+		}
+
+		summary.Rows++
+		if row.Valid() {
+			summary.Valid++
+		} else {
+			summary.Invalid++
+		}
+		return handle(row)
+	}
+
+	var err error
+	switch format {
+	case ImportCSV:
+		err = readCSVRecords(r, process)
+	case ImportJSON:
+		err = readJSONRecords(r, process)
+	default:
+		err = fmt.Errorf("%w: unknown format %d", ErrImportFormat, format)
+	}
+	return summary, err
+}
+
+// Report validates r and writes the rows with errors, followed by the
+// summary, to rep.
+func (b BulkValidator) Report(r io.Reader, format ImportFormat, rep ImportReporter) (ImportSummary, error) {
+	summary, err := b.Validate(r, format, func(row ImportRow) error {
+		if row.Valid() {
+			return nil
+		}
+		return rep.Row(row)
+	})
+	if err != nil {
+		return summary, err
+	}
+	return summary, rep.Close(summary)
+}
+
+func duplicateError(path, value string, err error, firstLine int) *FieldError {
+	return NewFieldError(path, value, &ParamError{
+		Err:     err,
+		Params:  map[string]any{"line": firstLine},
+		Message: fmt.Sprintf("%v (line %d)", err, firstLine),
+	})
+}
+
+// importEmailKey identifies a mailbox for duplicate detection. Addresses
+// that cannot be canonicalized fall back to their normalized text.
+func importEmailKey(email string) string {
+	if canonical, err := CanonicalEmail(email); err == nil {
+		return canonical
+	}
+	return NormalizeUsername(email)
+}
+
+// importRecord holds a record's fields as text, before conversion.
+type importRecord struct {
+	Username    string          `json:"username"`
+	Email       string          `json:"email"`
+	Age         json.RawMessage `json:"age"`
+	DateOfBirth string          `json:"date_of_birth"`
+	Password    string          `json:"password"`
+	Phone       string          `json:"phone"`
+
+	// errs records fields that could not be read.
+	errs ValidationErrors
+}
+
+// importFieldPaths maps record keys to User field paths.
+var importFieldPaths = map[string]string{
+	"username":      "Username",
+	"email":         "Email",
+	"age":           "Age",
+	"date_of_birth": "DateOfBirth",
+	"password":      "Password",
+	"phone":         "Phone",
+}
+
+// user converts the record, reporting fields that could not be read.
+func (rec importRecord) user() (User, ValidationErrors) {
+	u := User{Username: rec.Username, Email: rec.Email, Password: rec.Password, Phone: rec.Phone}
+	errs := rec.errs
+
+	if age := strings.Trim(strings.TrimSpace(string(rec.Age)), `"`); age != "" && age != "null" {
+		n, err := strconv.Atoi(age)
+		if err != nil {
+			errs = append(errs, NewFieldError("Age", age, fmt.Errorf("%w: %q is not a whole number", ErrImportInvalidValue, age)))
+		}
+		u.Age = n
+	}
+	if dob := strings.TrimSpace(rec.DateOfBirth); dob != "" {
+		t, err := time.Parse(time.DateOnly, dob)
+		if err != nil {
+			t, err = time.Parse(time.RFC3339, dob)
+		}
+		if err != nil {
+			errs = append(errs, NewFieldError("DateOfBirth", dob, fmt.Errorf("%w: %q is not a date", ErrImportInvalidValue, dob)))
+		}
+		u.DateOfBirth = t
+	}
+	return u, errs
+}
+
+var importColumns = map[string]string{
+	"username":      "username",
+	"email":         "email",
+	"age":           "age",
+	"date_of_birth": "date_of_birth",
+	"dateofbirth":   "date_of_birth",
+	"dob":           "date_of_birth",
+	"password":      "password",
+	"phone":         "phone",
+	"phone_number":  "phone",
+}
+
+func readCSVRecords(r io.Reader, process func(int, importRecord) error) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrImportFormat, err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if key, ok := importColumns[strings.ReplaceAll(name, " ", "_")]; ok {
+			columns[key] = i
+		}
+	}
+	for _, required := range []string{"username", "email"} {
+		if _, ok := columns[required]; !ok {
+			return fmt.Errorf("%w: missing %s column", ErrImportFormat, required)
+		}
+	}
+
+	for {
+		fields, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrImportFormat, err)
+		}
+		line, _ := cr.FieldPos(0)
+		get := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(fields) {
+				return fields[i]
+			}
+			return ""
+		}
+		rec := importRecord{
+			Username:    get("username"),
+			Email:       get("email"),
+			DateOfBirth: get("date_of_birth"),
+			Password:    get("password"),
+			Phone:       get("phone"),
+		}
+		if age := get("age"); age != "" {
+			rec.Age = json.RawMessage(strconv.Quote(age))
+		}
+		if err := process(line, rec); err != nil {
+			return err
+		}
+	}
+}
+
+func readJSONRecords(r io.Reader, process func(int, importRecord) error) error {
+	lines := &lineCounter{r: r}
+	br := bufio.NewReader(lines)
+	dec := json.NewDecoder(br)
+
+	first, err := firstNonSpace(br)
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrImportFormat, err)
+	}
+	array := first == '['
+	if array {
+		if _, err := dec.Token(); err != nil {
+			return fmt.Errorf("%w: %w", ErrImportFormat, err)
+		}
+	}
+
+	for array && dec.More() || !array {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err == io.EOF && !array {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("%w: line %d: %w", ErrImportFormat, lines.lineAt(dec.InputOffset()), err)
+		}
+		line := lines.lineAt(dec.InputOffset() - int64(len(raw)))
+
+		var rec importRecord
+		var typeErr *json.UnmarshalTypeError
+		if err := json.Unmarshal(raw, &rec); errors.As(err, &typeErr) {
+			// The other fields were still read, so the row is validated
+			// as far as possible.
+			err := fmt.Errorf("%w: record must be an object, not %s", ErrImportInvalidValue, typeErr.Value)
+			if typeErr.Field != "" {
+				err = fmt.Errorf("%w: %s must be a %s, not %s", ErrImportInvalidValue, typeErr.Field, typeErr.Type, typeErr.Value)
+			}
+			rec.errs = append(rec.errs, NewFieldError(importFieldPaths[typeErr.Field], nil, err))
+		} else if err != nil {
+			rec.errs = append(rec.errs, NewFieldError("", nil, fmt.Errorf("%w: %w", ErrImportInvalidValue, err)))
+		}
+		if err := process(line, rec); err != nil {
+			return err
+		}
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return fmt.Errorf("%w: %w", ErrImportFormat, err)
+	}
+	return nil
+}
+
+// firstNonSpace peeks at the first byte that is not white space.
+func firstNonSpace(br *bufio.Reader) (byte, error) {
+	for n := 1; ; n++ {
+		b, err := br.Peek(n)
+		if len(b) < n {
+			if err == nil {
+				err = io.EOF
+			}
+			return 0, err
+		}
+		if c := b[n-1]; c != ' ' && c != '\t' && c != '\r' && c != '\n' {
+			return c, nil
+		}
+	}
+}
+
+// lineCounter maps byte offsets to line numbers while the data streams
+// through. It only remembers newlines beyond the last offset looked up,
+// so offsets must be looked up in increasing order.
+type lineCounter struct {
+	r        io.Reader
+	read     int64
+	newlines []int64
+	line     int
+}
+
+func (c *lineCounter) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	for i, b := range p[:n] {
+		if b == '\n' {
+			c.newlines = append(c.newlines, c.read+int64(i))
+		}
+	}
+	c.read += int64(n)
+	return n, err
+}
+
+func (c *lineCounter) lineAt(offset int64) int {
+	for len(c.newlines) > 0 && c.newlines[0] < offset {
+		c.newlines = c.newlines[1:]
+		c.line++
+	}
+	return c.line + 1
+}
@@ -0,0 +1,116 @@
+package basics
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// ImportReporter writes the results of BulkValidator.Report as they are
+// produced.
+type ImportReporter interface {
+	// Row records a row with errors.
+	Row(row ImportRow) error
+	// Close records the summary and finishes the report.
+	Close(summary ImportSummary) error
+}
+
+// JSONImportReport writes a report of the form
+//
+//	{"rows": [{"line": 3, "errors": [...]}, ...], "summary": {...}}
+//
+// where each error has the same shape as in a ValidationErrors response.
+type JSONImportReport struct {
+	w    *bufio.Writer
+	rows int
+}
+
+func NewJSONImportReport(w io.Writer) *JSONImportReport {
+	return &JSONImportReport{w: bufio.NewWriter(w)}
+}
+
+func (r *JSONImportReport) Row(row ImportRow) error {
+	// A plain slice, since ValidationErrors marshals as a whole response.
+	data, err := json.Marshal(struct {
+		Line   int           `json:"line"`
+		Errors []*FieldError `json:"errors"`
+	}{row.Line, row.Errors})
+	if err != nil {
+		return err
+	}
+
+	sep := ",\n"
+	if r.rows == 0 {
+		sep = "{\"rows\":[\n"
+	}
+	r.rows++
+	if _, err := r.w.WriteString(sep); err != nil {
+		return err
+	}
+	_, err = r.w.Write(data)
+	return err
+}
+
+func (r *JSONImportReport) Close(summary ImportSummary) error {
+	data, err := json.Marshal(summary)
+	if err != nil {
+		return err
+	}
+	if r.rows == 0 {
+		r.w.WriteString("{\"rows\":[")
+	} else {
+		r.w.WriteString("\n")
+	}
+	fmt.Fprintf(r.w, "],\"summary\":%s}\n", data)
+	return r.w.Flush()
+}
+
+// CSVImportReport writes one line per error with the columns line, field,
+// code and message. The summary follows as "#" comment lines, which
+// csv.Reader skips when its Comment field is set to '#'.
+type CSVImportReport struct {
+	w      io.Writer
+	cw     *csv.Writer
+	header bool
+}
+
+func NewCSVImportReport(w io.Writer) *CSVImportReport {
+	return &CSVImportReport{w: w, cw: csv.NewWriter(w)}
+}
+
+func (r *CSVImportReport) writeHeader() error {
+	if r.header {
+		return nil
+	}
+	r.header = true
+	return r.cw.Write([]string{"line", "field", "code", "message"})
+}
+
+func (r *CSVImportReport) Row(row ImportRow) error {
+	if err := r.writeHeader(); err != nil {
+		return err
+	}
+	line := strconv.Itoa(row.Line)
+	for _, e := range row.Errors {
+		if err := r.cw.Write([]string{line, e.Path, e.Code, e.Message}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *CSVImportReport) Close(summary ImportSummary) error {
+	if err := r.writeHeader(); err != nil {
+		return err
+	}
+	r.cw.Flush()
+	if err := r.cw.Error(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(r.w, "# rows=%d valid=%d invalid=%d duplicate_usernames=%d duplicate_emails=%d\n",
+		summary.Rows, summary.Valid, summary.Invalid, summary.DuplicateUsernames, summary.DuplicateEmails)
+	return err
+}
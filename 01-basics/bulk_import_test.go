@@ -0,0 +1,271 @@
+package basics
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+const importCSV = `Username,Email,Age,Date of Birth,Notes
+johndoe,john@example.com,30,,first
+JohnDoe,other@example.com,31,,duplicate username
+janedoe,John.Doe+x@Example.com,25,,not a duplicate
+jane2,jane@gmail.com,abc,,bad age
+jane3,j.a.n.e+promo@googlemail.com,22,1990-13-01,duplicate email and bad date
+"multi
+line",x@,40,,invalid username and email
+`
+
+type importResult struct {
+	line  int
+	valid bool
+	errs  []error
+}
+
+func collectImport(t *testing.T, input string, format ImportFormat) ([]importResult, ImportSummary) {
+	t.Helper()
+	var got []importResult
+	summary, err := BulkValidator{}.Validate(strings.NewReader(input), format, func(row ImportRow) error {
+		res := importResult{line: row.Line, valid: row.Valid()}
+		for _, e := range row.Errors {
+			res.errs = append(res.errs, e)
+		}
+		got = append(got, res)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Validate error = %v", err)
+	}
+	return got, summary
+}
+
+func checkImport(t *testing.T, got []importResult, want []importResult) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d rows; want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.line != w.line || g.valid != w.valid || len(g.errs) != len(w.errs) {
+			t.Errorf("row %d = {line %d valid %v errs %v}; want {line %d valid %v, %d errors}",
+				i, g.line, g.valid, g.errs, w.line, w.valid, len(w.errs))
+			continue
+		}
+		for j, err := range w.errs {
+			if !errors.Is(g.errs[j], err) {
+				t.Errorf("row %d error %d = %v; want %v", i, j, g.errs[j], err)
+			}
+		}
+	}
+}
+
+func TestBulkValidatorCSV(t *testing.T) {
+	got, summary := collectImport(t, importCSV, ImportCSV)
+	checkImport(t, got, []importResult{
+		{2, true, nil},
+		{3, false, []error{ErrDuplicateUsername}},
+		{4, true, nil},
+		{5, false, []error{ErrImportInvalidValue}},
+		{6, false, []error{ErrImportInvalidValue, ErrDuplicateEmail}},
+		{7, false, []error{ErrUsernameInvalidChar, ErrEmailDomain}},
+	})
+
+	want := ImportSummary{Rows: 6, Valid: 2, Invalid: 4, DuplicateUsernames: 1, DuplicateEmails: 1}
+	if summary != want {
+		t.Errorf("summary = %+v; want %+v", summary, want)
+	}
+}
+
+func TestBulkValidatorJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		lines [4]int
+	}{
+		{"array", `[
+  {"username": "johndoe", "email": "john@example.com", "age": 30},
+  {"username": "johndoe", "email": "other@example.com", "age": "31"},
+  {"username": 42, "email": "x@example.com"},
+  {"username": "janedoe", "email": "jane@example.com", "date_of_birth": "2099-01-01"}
+]`, [4]int{2, 3, 4, 5}},
+		{"newline delimited", `{"username": "johndoe", "email": "john@example.com", "age": 30}
+{"username": "johndoe", "email": "other@example.com", "age": "31"}
+{"username": 42, "email": "x@example.com"}
+
+{"username": "janedoe", "email": "jane@example.com", "date_of_birth": "2099-01-01"}
+`, [4]int{1, 2, 3, 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, summary := collectImport(t, tt.input, ImportJSON)
+			checkImport(t, got, []importResult{
+				{tt.lines[0], true, nil},
+				{tt.lines[1], false, []error{ErrDuplicateUsername}},
+				{tt.lines[2], false, []error{ErrImportInvalidValue}},
+				{tt.lines[3], false, []error{ErrDateOfBirthInFuture}},
+			})
+			if summary.Rows != 4 || summary.Invalid != 3 {
+				t.Errorf("summary = %+v", summary)
+			}
+		})
+	}
+}
+
+func TestBulkValidatorPhoneColumn(t *testing.T) {
+	input := `username,email,phone
+johndoe,john@example.com,+44 20 7946 0958
+janedoe,jane@example.com,020 7946 0958
+maxdoe,max@example.com,
+`
+	got, _ := collectImport(t, input, ImportCSV)
+	checkImport(t, got, []importResult{
+		{2, true, nil},
+		{3, false, []error{ErrPhoneCountryRequired}},
+		{4, true, nil},
+	})
+}
+
+func TestBulkValidatorFormatErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		format ImportFormat
+	}{
+		{"missing column", "username,age\njohndoe,30\n", ImportCSV},
+		{"bad quote", "username,email\n\"john,x@example.com\n", ImportCSV},
+		{"truncated JSON", `[{"username": "johndoe"`, ImportJSON},
+		{"unknown format", "", ImportFormat(9)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := BulkValidator{}.Validate(strings.NewReader(tt.input), tt.format, func(ImportRow) error { return nil })
+			if !errors.Is(err, ErrImportFormat) {
+				t.Errorf("Validate error = %v; want ErrImportFormat", err)
+			}
+		})
+	}
+
+	for format, input := range map[ImportFormat]string{ImportCSV: "", ImportJSON: "  \n"} {
+		summary, err := BulkValidator{}.Validate(strings.NewReader(input), format, func(ImportRow) error { return nil })
+		if err != nil || summary.Rows != 0 {
+			t.Errorf("empty input = %+v, %v; want no rows", summary, err)
+		}
+	}
+}
+
+func TestBulkValidatorStopsOnHandlerError(t *testing.T) {
+	stop := errors.New("stop")
+	calls := 0
+	_, err := BulkValidator{}.Validate(strings.NewReader(importCSV), ImportCSV, func(ImportRow) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("Validate = %v after %d calls; want stop after 1", err, calls)
+	}
+}
+
+// endlessCSV produces rows without end, to show the validator does not
+// read ahead.
+type endlessCSV struct {
+	n       int
+	pending string
+}
+
+func (r *endlessCSV) Read(p []byte) (int, error) {
+	if r.pending == "" {
+		if r.n == 0 {
+			r.pending = "username,email\n"
+		} else {
+			r.pending = "user" + strings.Repeat("x", r.n%7) + string(rune('a'+r.n%26)) + ",u@example.com\n"
+		}
+		r.n++
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+func TestBulkValidatorStreams(t *testing.T) {
+	src := &endlessCSV{}
+	rows := 0
+	stop := errors.New("enough")
+	_, err := BulkValidator{}.Validate(src, ImportCSV, func(ImportRow) error {
+		rows++
+		if rows == 1000 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) {
+		t.Fatalf("Validate error = %v; want stop", err)
+	}
+	if src.n > 1100 {
+		t.Errorf("read %d rows to handle 1000; want streaming", src.n)
+	}
+}
+
+func TestJSONImportReport(t *testing.T) {
+	var out strings.Builder
+	summary, err := BulkValidator{}.Report(strings.NewReader(importCSV), ImportCSV, NewJSONImportReport(&out))
+	if err != nil {
+		t.Fatalf("Report error = %v", err)
+	}
+
+	var report struct {
+		Rows []struct {
+			Line   int `json:"line"`
+			Errors []struct {
+				Field  string         `json:"field"`
+				Code   string         `json:"code"`
+				Params map[string]any `json:"params"`
+			} `json:"errors"`
+		} `json:"rows"`
+		Summary ImportSummary `json:"summary"`
+	}
+	if err := json.Unmarshal([]byte(out.String()), &report); err != nil {
+		t.Fatalf("report is not JSON: %v\n%s", err, out.String())
+	}
+	if report.Summary != summary || len(report.Rows) != summary.Invalid {
+		t.Errorf("report summary %+v with %d rows; want %+v", report.Summary, len(report.Rows), summary)
+	}
+	dup := report.Rows[0]
+	if dup.Line != 3 || dup.Errors[0].Code != "username.duplicate" || dup.Errors[0].Params["line"] != 2.0 {
+		t.Errorf("first row = %+v; want duplicate of line 2 on line 3", dup)
+	}
+
+	out.Reset()
+	if _, err := (BulkValidator{}).Report(strings.NewReader("username,email\njohndoe,john@example.com\n"), ImportCSV, NewJSONImportReport(&out)); err != nil {
+		t.Fatalf("Report error = %v", err)
+	}
+	if want := `{"rows":[],"summary":{"rows":1,"valid":1,"invalid":0,"duplicate_usernames":0,"duplicate_emails":0}}` + "\n"; out.String() != want {
+		t.Errorf("clean report = %q; want %q", out.String(), want)
+	}
+}
+
+func TestCSVImportReport(t *testing.T) {
+	var out strings.Builder
+	if _, err := (BulkValidator{}).Report(strings.NewReader(importCSV), ImportCSV, NewCSVImportReport(&out)); err != nil {
+		t.Fatalf("Report error = %v", err)
+	}
+
+	r := csv.NewReader(strings.NewReader(out.String()))
+	r.Comment = '#'
+	records, err := r.ReadAll()
+	if err != nil && err != io.EOF {
+		t.Fatalf("report is not CSV: %v", err)
+	}
+	if len(records) != 7 || strings.Join(records[0], ",") != "line,field,code,message" {
+		t.Fatalf("records = %q", records)
+	}
+	if got := strings.Join(records[1][:3], ","); got != "3,Username,username.duplicate" {
+		t.Errorf("first error = %q", got)
+	}
+	if !strings.HasSuffix(out.String(), "# rows=6 valid=2 invalid=4 duplicate_usernames=1 duplicate_emails=1\n") {
+		t.Errorf("report does not end with the summary:\n%s", out.String())
+	}
+}
@@ -2,6 +2,8 @@ package basics
 
 import "errors"
 
+var ErrDivisionByZero = errors.New("division by zero")
+
 type Calculator struct{}
 
 func (c *Calculator) Add(a, b int) int {
@@ -18,21 +20,34 @@ func (c *Calculator) Multiply(a, b int) int {
 
 func (c *Calculator) Divide(a, b int) (int, error) {
 	if b == 0 {
-		return 0, errors.New("division by zero")
+		return 0, ErrDivisionByZero
 	}
 
 	return a / b, nil
 }
 
+// Power raises base to a non-negative exponent by repeated squaring, so
+// large exponents take logarithmic time. The result wraps on overflow.
+// A negative exponent returns base unchanged.
 func (c *Calculator) Power(base, exp int) int {
-	if exp == 0 {
-		return 1
+	if exp < 0 {
+		return base
 	}
+	return powerBySquaring(base, exp, 1, func(a, b int) int { return a * b })
+}
 
-	result := base
-	for i := 1; i < exp; i++ {
-		result *= base
+// powerBySquaring raises base to a non-negative exponent with a number of
+// multiplications logarithmic in exp, starting from one. It is shared by
+// the integer, complex and polynomial powers.
+func powerBySquaring[T any](base T, exp int, one T, mul func(a, b T) T) T {
+	result := one
+	for ; exp > 0; exp >>= 1 {
+		if exp&1 == 1 {
+			result = mul(result, base)
+		}
+		if exp > 1 {
+			base = mul(base, base)
+		}
 	}
-
 	return result
 }
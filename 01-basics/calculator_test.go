@@ -91,6 +91,12 @@ func TestPower(t *testing.T) {
 	if result != expected {
 		t.Errorf("Power(7, 1) = %d; want %d", result, expected)
 	}
+
+	result = calc.Power(3, -2)
+	expected = 3
+	if result != expected {
+		t.Errorf("Power(3, -2) = %d; want %d", result, expected)
+	}
 }
 
 func TestCalculatorMultipleOperations(t *testing.T) {
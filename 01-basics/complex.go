@@ -0,0 +1,42 @@
+package basics
+
+func (c *Calculator) AddComplex(a, b complex128) complex128 {
+	return a + b
+}
+
+func (c *Calculator) SubtractComplex(a, b complex128) complex128 {
+	return a - b
+}
+
+func (c *Calculator) MultiplyComplex(a, b complex128) complex128 {
+	return a * b
+}
+
+func (c *Calculator) DivideComplex(a, b complex128) (complex128, error) {
+	if b == 0 {
+		return 0, ErrDivisionByZero
+	}
+
+	return a / b, nil
+}
+
+// PowerComplex raises base to an integer exponent. Negative exponents are
+// computed as the reciprocal, so a zero base fails with ErrDivisionByZero.
+func (c *Calculator) PowerComplex(base complex128, exp int) (complex128, error) {
+	negative := exp < 0
+	if negative {
+		exp = -exp
+	}
+
+	result := powerBySquaring(base, exp, 1, func(a, b complex128) complex128 { return a * b })
+
+	if negative {
+		return c.DivideComplex(1, result)
+	}
+
+	return result, nil
+}
+
+func (c *Calculator) Conjugate(z complex128) complex128 {
+	return complex(real(z), -imag(z))
+}
@@ -0,0 +1,69 @@
+package basics
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestComplexArithmetic(t *testing.T) {
+	calc := Calculator{}
+	a, b := complex(1, 2), complex(3, -1)
+
+	if got, want := calc.AddComplex(a, b), complex(4, 1); got != want {
+		t.Errorf("AddComplex(%v, %v) = %v; want %v", a, b, got, want)
+	}
+	if got, want := calc.SubtractComplex(a, b), complex(-2, 3); got != want {
+		t.Errorf("SubtractComplex(%v, %v) = %v; want %v", a, b, got, want)
+	}
+	if got, want := calc.MultiplyComplex(a, b), complex(5, 5); got != want {
+		t.Errorf("MultiplyComplex(%v, %v) = %v; want %v", a, b, got, want)
+	}
+	if got, want := calc.Conjugate(a), complex(1, -2); got != want {
+		t.Errorf("Conjugate(%v) = %v; want %v", a, got, want)
+	}
+
+	got, err := calc.DivideComplex(complex(-1, 3), complex(1, 1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != a {
+		t.Errorf("DivideComplex(-1+3i, 1+1i) = %v; want %v", got, a)
+	}
+}
+
+func TestDivideComplexByZero(t *testing.T) {
+	calc := Calculator{}
+	_, err := calc.DivideComplex(complex(1, 1), 0)
+
+	if !errors.Is(err, ErrDivisionByZero) {
+		t.Errorf("error = %v; want ErrDivisionByZero", err)
+	}
+}
+
+func TestPowerComplex(t *testing.T) {
+	calc := Calculator{}
+	tests := []struct {
+		name    string
+		base    complex128
+		exp     int
+		want    complex128
+		wantErr error
+	}{
+		{"i squared", complex(0, 1), 2, -1, nil},
+		{"zero exponent", complex(3, 4), 0, 1, nil},
+		{"negative exponent", complex(0, 2), -1, complex(0, -0.5), nil},
+		{"zero base negative exponent", 0, -2, 0, ErrDivisionByZero},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := calc.PowerComplex(tt.base, tt.exp)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("PowerComplex(%v, %d) error = %v; want %v", tt.base, tt.exp, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("PowerComplex(%v, %d) = %v; want %v", tt.base, tt.exp, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package basics
+
+import (
+	"bufio"
+	_ "embed"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	ErrUsernameMixedScript = errors.New("username mixes characters from different scripts")
+	ErrUsernameConfusable  = errors.New("username is confusable with an existing username")
+)
+
+//go:embed data/confusables.txt
+var confusablesData string
+
+// confusables maps a character to its prototype, parsed once from the
+// embedded snapshot of the UTS #39 confusables.txt file.
+var confusables = sync.OnceValue(func() map[rune]string {
+	table, err := parseConfusables(confusablesData)
+	if err != nil {
+		panic(err)
+	}
+	return table
+})
+
+func parseConfusables(data string) (map[rune]string, error) {
+	table := make(map[rune]string)
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text, _, _ := strings.Cut(scanner.Text(), "#")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		fields := strings.Split(text, ";")
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("confusables line %d: expected source ; target", line)
+		}
+		source, err := parseCodePoints(fields[0])
+		if err != nil || len(source) != 1 {
+			return nil, fmt.Errorf("confusables line %d: bad source %q", line, fields[0])
+		}
+		target, err := parseCodePoints(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("confusables line %d: bad target %q", line, fields[1])
+		}
+		table[source[0]] = string(target)
+	}
+	return table, scanner.Err()
+}
+
+func parseCodePoints(s string) ([]rune, error) {
+	var runes []rune
+	for _, hex := range strings.Fields(s) {
+		n, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil {
+			return nil, err
+		}
+		runes = append(runes, rune(n))
+	}
+	return runes, nil
+}
+
+// Skeleton computes the UTS #39 skeleton of s: two strings are visually
+// confusable when their skeletons are equal. "pаypal" with a Cyrillic "а"
+// and "paypaI" with a capital "I" both have the skeleton "paypal".
+func Skeleton(s string) string {
+	table := confusables()
+	var b strings.Builder
+	for _, r := range norm.NFD.String(s) {
+		if prototype, ok := table[r]; ok {
+			b.WriteString(prototype)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return norm.NFD.String(b.String())
+}
+
+// usernameSkeletons extends Skeleton to ignore case, since usernames are
+// compared case-insensitively. The first skeleton is that of the username
+// folded by NormalizeUsername, so "Ian" and "ian" match. The second, if
+// different, is that of the username as typed, so that a capital "I" still
+// maps to "l" and "paypaI" matches "paypal". Prototypes such as "O" are
+// lowercased too.
+func usernameSkeletons(username string) []string {
+	folded := strings.ToLower(Skeleton(NormalizeUsername(username)))
+	typed := strings.ToLower(Skeleton(strings.TrimSpace(username)))
+	if typed == folded {
+		return []string{folded}
+	}
+	return []string{folded, typed}
+}
+
+// Confusable reports whether two usernames could be mistaken for each other.
+func Confusable(a, b string) bool {
+	skeletons := usernameSkeletons(b)
+	for _, skeleton := range usernameSkeletons(a) {
+		if slices.Contains(skeletons, skeleton) {
+			return true
+		}
+	}
+	return false
+}
+
+// ConfusableSet indexes existing usernames by skeleton so that new ones can
+// be checked against them quickly. It can be used as a Rule[string].
+type ConfusableSet struct {
+	mu        sync.RWMutex
+	skeletons map[string]string
+}
+
+func NewConfusableSet(usernames ...string) *ConfusableSet {
+	s := &ConfusableSet{skeletons: make(map[string]string, len(usernames))}
+	s.Add(usernames...)
+	return s
+}
+
+// Add records usernames as taken.
+func (s *ConfusableSet) Add(usernames ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, name := range usernames {
+		for _, skeleton := range usernameSkeletons(name) {
+			if _, ok := s.skeletons[skeleton]; !ok {
+				s.skeletons[skeleton] = name
+			}
+		}
+	}
+}
+
+// Find returns an existing username confusable with username, if any. An
+// exact match counts as confusable.
+func (s *ConfusableSet) Find(username string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, skeleton := range usernameSkeletons(username) {
+		if existing, ok := s.skeletons[skeleton]; ok {
+			return existing, true
+		}
+	}
+	return "", false
+}
+
+// Validate fails with ErrUsernameConfusable if username looks like one
+// already in the set.
+func (s *ConfusableSet) Validate(username string) error {
+	if existing, ok := s.Find(username); ok {
+		return &ParamError{
+			Err:     ErrUsernameConfusable,
+			Params:  map[string]any{"existing": existing},
+			Message: fmt.Sprintf("%v %q", ErrUsernameConfusable, existing),
+		}
+	}
+	return nil
+}
+
+// Scripts returns the names of the scripts used in s, ignoring characters
+// shared between scripts such as digits, punctuation and combining marks.
+func Scripts(s string) []string {
+	var names []string
+	for _, r := range s {
+		if name := scriptOf(r); name != "" && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// IsSingleScript reports whether s could be written in a single script,
+// following the UTS #39 resolved script set: Han may be mixed with the
+// Japanese kana or with Korean Hangul, as those writing systems do.
+func IsSingleScript(s string) bool {
+	var resolved []string
+	first := true
+	for _, name := range Scripts(s) {
+		set := augmentedScripts(name)
+		if first {
+			resolved, first = set, false
+			continue
+		}
+		resolved = slices.DeleteFunc(resolved, func(n string) bool { return !slices.Contains(set, n) })
+	}
+	return first || len(resolved) > 0
+}
+
+// SingleScript is a rule rejecting values that mix scripts, such as a Latin
+// username with a Cyrillic look-alike letter.
+var SingleScript = RuleFunc[string](func(value string) error {
+	if IsSingleScript(value) {
+		return nil
+	}
+	scripts := strings.Join(Scripts(value), ", ")
+	return &ParamError{
+		Err:     ErrUsernameMixedScript,
+		Params:  map[string]any{"scripts": scripts},
+		Message: fmt.Sprintf("%v (%s)", ErrUsernameMixedScript, scripts),
+	}
+})
+
+func augmentedScripts(name string) []string {
+	switch name {
+	case "Han":
+		return []string{"Han", "Japanese", "Korean", "Bopomofo"}
+	case "Hiragana", "Katakana":
+		return []string{"Japanese"}
+	case "Hangul":
+		return []string{"Korean"}
+	case "Bopomofo":
+		return []string{"Bopomofo"}
+	}
+	return []string{name}
+}
+
+// scriptOf returns the script of r, or "" for Common and Inherited
+// characters.
+func scriptOf(r rune) string {
+	if unicode.In(r, unicode.Common, unicode.Inherited) {
+		return ""
+	}
+	if unicode.Is(unicode.Latin, r) {
+		return "Latin"
+	}
+	for name, table := range unicode.Scripts {
+		if unicode.Is(table, r) {
+			return name
+		}
+	}
+	return ""
+}
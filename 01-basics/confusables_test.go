@@ -0,0 +1,155 @@
+package basics
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestSkeleton(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"paypal", "paypal"},
+		{"paypaI", "paypal"},
+		{"pаypаl", "paypal"},
+		{"g00gle", "gOOgle"},
+		{"modern", "rnodern"},
+		{"РауРаl", "PayPal"},
+		{"lınk", "link"},
+		{"\uff50\uff41\uff50\uff41", "papa"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := Skeleton(tt.input); got != tt.want {
+				t.Errorf("Skeleton(%q) = %q; want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfusable(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"paypal", "paypaI", true},
+		{"paypal", "PAYPAL", true},
+		{"paypal", "раураl", true},
+		{"google", "g00gle", true},
+		{"modern", "rnodern", true},
+		{"Ian", "ian", true},
+		{"IAN", "ian", true},
+		{"ALICE", "alice", true},
+		{"Straße", "STRASSE", true},
+		{"alice", "alicia", false},
+		{"bob", "rob", false},
+	}
+
+	for _, tt := range tests {
+		if got := Confusable(tt.a, tt.b); got != tt.want {
+			t.Errorf("Confusable(%q, %q) = %v; want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestConfusableSet(t *testing.T) {
+	set := NewConfusableSet("paypal", "Google", "admin_team", "ian", "alice")
+
+	tests := []struct {
+		input    string
+		existing string
+	}{
+		{"paypaI", "paypal"},
+		{"pаypal", "paypal"},
+		{"G00GLE", "Google"},
+		{"adrnin_team", "admin_team"},
+		{"Ian", "ian"},
+		{"IAN", "ian"},
+		{"ALICE", "alice"},
+		{"Alíce", ""},
+		{"stripe", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			existing, found := set.Find(tt.input)
+			if existing != tt.existing || found != (tt.existing != "") {
+				t.Errorf("Find(%q) = %q, %v; want %q", tt.input, existing, found, tt.existing)
+			}
+
+			err := set.Validate(tt.input)
+			if found != errors.Is(err, ErrUsernameConfusable) {
+				t.Errorf("Validate(%q) error = %v", tt.input, err)
+			}
+		})
+	}
+
+	set.Add("stripe")
+	if _, found := set.Find("str1pe"); found {
+		t.Error("Find(str1pe) matched, but 1 maps to l, not i")
+	}
+	if _, found := set.Find("stripe"); !found {
+		t.Error("Find(stripe) after Add = false; want true")
+	}
+}
+
+func TestIsSingleScript(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"paypal", true},
+		{"user_42", true},
+		{"вася", true},
+		{"pаypal", false},
+		{"αlpha", false},
+		{"山田さん", true},
+		{"タロウ太郎", true},
+		{"한국山", true},
+		{"ひ한", false},
+		{"123", true},
+	}
+
+	for _, tt := range tests {
+		if got := IsSingleScript(tt.input); got != tt.want {
+			t.Errorf("IsSingleScript(%q) = %v (scripts %v); want %v", tt.input, got, Scripts(tt.input), tt.want)
+		}
+	}
+}
+
+func TestUsernamePolicyRejectsMixedScript(t *testing.T) {
+	err := ValidateUsername("pаypal")
+	if !errors.Is(err, ErrUsernameMixedScript) {
+		t.Fatalf("ValidateUsername(mixed) error = %v; want ErrUsernameMixedScript", err)
+	}
+	fe := NewFieldError("Username", "pаypal", err)
+	if fe.Code != "username.mixed_script" || fe.Params["scripts"] != "Cyrillic, Latin" {
+		t.Errorf("FieldError = {%s %v}; want username.mixed_script with Cyrillic, Latin", fe.Code, fe.Params)
+	}
+
+	if err := ValidateUsername("вася"); err != nil {
+		t.Errorf("ValidateUsername(all Cyrillic) returned error: %v", err)
+	}
+
+	policy := DefaultUsernamePolicy
+	policy.SingleScript = false
+	if err := policy.Validate("pаypal"); err != nil {
+		t.Errorf("Validate with SingleScript disabled returned error: %v", err)
+	}
+}
+
+func TestConfusablesDataParses(t *testing.T) {
+	table := confusables()
+	if len(table) < 50 {
+		t.Errorf("confusables table has %d entries; want the embedded snapshot", len(table))
+	}
+	if _, err := parseConfusables("0430 ; zz ; MA\n"); err == nil {
+		t.Error("parseConfusables accepted a bad target")
+	}
+	if got := Scripts("a\u0430-1\u0301"); !slices.Equal(got, []string{"Cyrillic", "Latin"}) {
+		t.Errorf("Scripts = %v; want [Cyrillic Latin]", got)
+	}
+}
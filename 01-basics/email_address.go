@@ -0,0 +1,345 @@
+package basics
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Specific email errors. Each one also matches ErrInvalidEmail with
+// errors.Is.
+var (
+	ErrEmailMissingAt   = fmt.Errorf("%w: missing @", ErrInvalidEmail)
+	ErrEmailLocalPart   = fmt.Errorf("%w: invalid local part", ErrInvalidEmail)
+	ErrEmailDomain      = fmt.Errorf("%w: invalid domain", ErrInvalidEmail)
+	ErrEmailTooLong     = fmt.Errorf("%w: address too long", ErrInvalidEmail)
+	ErrEmailDisplayName = fmt.Errorf("%w: display name not allowed", ErrInvalidEmail)
+	ErrEmailNonASCII    = fmt.Errorf("%w: non-ASCII characters not allowed", ErrInvalidEmail)
+)
+
+// Length limits from RFC 5321 section 4.5.3.1.
+const (
+	maxLocalPartLength = 64
+	maxDomainLength    = 253
+	maxAddressLength   = 254
+	maxLabelLength     = 63
+)
+
+// EmailMode selects how strictly addresses are parsed.
+type EmailMode int
+
+const (
+	// EmailStrict accepts only a bare RFC 5321 mailbox ("local@domain")
+	// whose domain is a fully qualified host name or an address literal.
+	EmailStrict EmailMode = iota
+	// EmailLenient additionally accepts an RFC 5322 display name
+	// ("Jane Doe <jane@example.com>") and single-label intranet hosts.
+	EmailLenient
+)
+
+// EmailAddress is a parsed email address.
+type EmailAddress struct {
+	DisplayName string
+	// LocalPart is unquoted: for "\"john doe\"@example.com" it is
+	// "john doe". It may contain UTF-8 (RFC 6531).
+	LocalPart string
+	// Domain is a host name as written, possibly in Unicode, or an address
+	// literal including its brackets, such as "[192.0.2.1]".
+	Domain string
+}
+
+// IsInternational reports whether the address needs SMTPUTF8 or IDNA
+// handling to be delivered.
+func (a *EmailAddress) IsInternational() bool {
+	return !isASCII(a.LocalPart) || !isASCII(a.Domain)
+}
+
+// ASCII returns the addr-spec with the domain converted to punycode, for
+// systems without SMTPUTF8 support. It fails with ErrEmailNonASCII if the
+// local part itself is not ASCII, since that cannot be converted.
+func (a *EmailAddress) ASCII() (string, error) {
+	if !isASCII(a.LocalPart) {
+		return "", ErrEmailNonASCII
+	}
+	domain := a.Domain
+	if !strings.HasPrefix(domain, "[") {
+		var err error
+		if domain, err = DomainToASCII(domain); err != nil {
+			return "", fmt.Errorf("%w: %w", ErrEmailDomain, err)
+		}
+	}
+	return quoteLocalPart(a.LocalPart) + "@" + domain, nil
+}
+
+// Address returns the addr-spec form, quoting the local part if needed.
+func (a *EmailAddress) Address() string {
+	return quoteLocalPart(a.LocalPart) + "@" + a.Domain
+}
+
+func (a *EmailAddress) String() string {
+	if a.DisplayName == "" {
+		return a.Address()
+	}
+	return quoteDisplayName(a.DisplayName) + " <" + a.Address() + ">"
+}
+
+// EmailPolicy configures email parsing and validation.
+type EmailPolicy struct {
+	Mode EmailMode
+	// ASCIIOnly rejects UTF-8 local parts and Unicode domains. Punycode
+	// ("xn--") domains are still accepted.
+	ASCIIOnly bool
+	// RejectDisposable and RejectRoleAccounts turn the signals of an
+	// EmailRiskChecker into hard failures. Risk selects the checker; nil
+	// means DefaultEmailRiskChecker.
+	RejectDisposable   bool
+	RejectRoleAccounts bool
+	Risk               *EmailRiskChecker
+}
+
+// DefaultEmailPolicy is the policy used by ParseEmail and ValidateEmail.
+var DefaultEmailPolicy = EmailPolicy{Mode: EmailStrict}
+
+// ParseEmail parses s using DefaultEmailPolicy.
+func ParseEmail(s string) (*EmailAddress, error) {
+	return DefaultEmailPolicy.Parse(s)
+}
+
+// Validate reports whether email is acceptable under the policy.
+func (p EmailPolicy) Validate(email string) error {
+	_, err := p.Parse(email)
+	return err
+}
+
+// Parse parses an email address according to the policy's mode.
+func (p EmailPolicy) Parse(s string) (*EmailAddress, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, ErrInvalidEmail
+	}
+	if !utf8.ValidString(s) {
+		return nil, fmt.Errorf("%w: malformed UTF-8", ErrInvalidEmail)
+	}
+	if p.ASCIIOnly && !isASCII(s) {
+		return nil, ErrEmailNonASCII
+	}
+
+	var display string
+	if strings.HasSuffix(s, ">") {
+		if p.Mode != EmailLenient {
+			return nil, ErrEmailDisplayName
+		}
+		open := strings.LastIndex(s, "<")
+		if open < 0 {
+			return nil, fmt.Errorf("%w: unbalanced angle brackets", ErrInvalidEmail)
+		}
+		name, err := parseDisplayName(strings.TrimSpace(s[:open]))
+		if err != nil {
+			return nil, err
+		}
+		display = name
+		s = s[open+1 : len(s)-1]
+	}
+
+	if len(s) > maxAddressLength {
+		return nil, ErrEmailTooLong
+	}
+
+	local, rest, err := parseLocalPart(s)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(rest, "@") {
+		return nil, ErrEmailMissingAt
+	}
+
+	domain := rest[1:]
+	if err := p.validateDomain(domain); err != nil {
+		return nil, err
+	}
+
+	addr := &EmailAddress{DisplayName: display, LocalPart: local, Domain: domain}
+	if err := p.riskError(addr); err != nil {
+		return nil, err
+	}
+	return addr, nil
+}
+
+// parseLocalPart reads a dot-string or quoted-string local part from the
+// start of s and returns it unquoted along with the remaining input.
+func parseLocalPart(s string) (string, string, error) {
+	if strings.HasPrefix(s, `"`) {
+		return parseQuotedLocalPart(s)
+	}
+
+	at := strings.IndexByte(s, '@')
+	if at < 0 {
+		return "", "", ErrEmailMissingAt
+	}
+	local := s[:at]
+	if local == "" || len(local) > maxLocalPartLength {
+		return "", "", ErrEmailLocalPart
+	}
+
+	for _, atom := range strings.Split(local, ".") {
+		// An empty atom means a leading, trailing or doubled dot.
+		if atom == "" {
+			return "", "", fmt.Errorf("%w: misplaced dot", ErrEmailLocalPart)
+		}
+		for _, r := range atom {
+			if !isAtextRune(r) {
+				return "", "", fmt.Errorf("%w: unexpected %q", ErrEmailLocalPart, r)
+			}
+		}
+	}
+	return local, s[at:], nil
+}
+
+func parseQuotedLocalPart(s string) (string, string, error) {
+	var b strings.Builder
+	escaped := false
+	for i, r := range s[1:] {
+		switch {
+		case escaped:
+			if r < 32 || r > 126 {
+				return "", "", fmt.Errorf("%w: bad escape", ErrEmailLocalPart)
+			}
+			b.WriteRune(r)
+			escaped = false
+		case r == '"':
+			end := i + 2
+			if b.Len() == 0 || end > maxLocalPartLength {
+				return "", "", ErrEmailLocalPart
+			}
+			return b.String(), s[end:], nil
+		case r == '\\':
+			escaped = true
+		case r >= 32 && r <= 126, r >= utf8.RuneSelf && unicode.IsGraphic(r):
+			b.WriteRune(r)
+		default:
+			return "", "", fmt.Errorf("%w: unexpected %q", ErrEmailLocalPart, r)
+		}
+	}
+	return "", "", fmt.Errorf("%w: unterminated quoted string", ErrEmailLocalPart)
+}
+
+func (p EmailPolicy) validateDomain(domain string) error {
+	if domain == "" {
+		return ErrEmailDomain
+	}
+	if strings.HasPrefix(domain, "[") {
+		return validateAddressLiteral(domain)
+	}
+
+	ascii, err := DomainToASCII(domain)
+	if err != nil {
+		if len(domain) > maxDomainLength {
+			return ErrEmailTooLong
+		}
+		return fmt.Errorf("%w: %w", ErrEmailDomain, err)
+	}
+
+	labels := strings.Split(ascii, ".")
+	if len(labels) < 2 && p.Mode != EmailLenient {
+		return fmt.Errorf("%w: %q is not a fully qualified domain", ErrEmailDomain, domain)
+	}
+
+	// A numeric top-level label would make the domain look like an IPv4
+	// address; RFC 3696 section 2 rules it out.
+	if tld := labels[len(labels)-1]; strings.Trim(tld, "0123456789") == "" {
+		return fmt.Errorf("%w: numeric top-level domain", ErrEmailDomain)
+	}
+	return nil
+}
+
+// validateHostLabel checks an RFC 1123 letter-digit-hyphen label.
+func validateHostLabel(label string) error {
+	if label == "" || len(label) > maxLabelLength {
+		return fmt.Errorf("%w: bad label length", ErrInvalidIDN)
+	}
+	if label[0] == '-' || label[len(label)-1] == '-' {
+		return fmt.Errorf("%w: label %q starts or ends with a hyphen", ErrInvalidIDN, label)
+	}
+	for i := 0; i < len(label); i++ {
+		c := label[i]
+		if !(isLetter(c) || isDigit(c) || c == '-') {
+			return fmt.Errorf("%w: unexpected %q", ErrInvalidIDN, c)
+		}
+	}
+	return nil
+}
+
+// validateAddressLiteral accepts "[192.0.2.1]" and "[IPv6:2001:db8::1]".
+func validateAddressLiteral(domain string) error {
+	if !strings.HasSuffix(domain, "]") {
+		return fmt.Errorf("%w: unterminated address literal", ErrEmailDomain)
+	}
+	literal := domain[1 : len(domain)-1]
+
+	if v6, ok := strings.CutPrefix(literal, "IPv6:"); ok {
+		if ip := net.ParseIP(v6); ip != nil && strings.Contains(v6, ":") {
+			return nil
+		}
+		return fmt.Errorf("%w: bad IPv6 literal", ErrEmailDomain)
+	}
+	if ip := net.ParseIP(literal); ip != nil && ip.To4() != nil && !strings.Contains(literal, ":") {
+		return nil
+	}
+	return fmt.Errorf("%w: bad address literal", ErrEmailDomain)
+}
+
+func parseDisplayName(name string) (string, error) {
+	if !strings.HasPrefix(name, `"`) {
+		if strings.ContainsAny(name, `"<>@,;:\`) {
+			return "", fmt.Errorf("%w: display name must be quoted", ErrInvalidEmail)
+		}
+		return strings.Join(strings.Fields(name), " "), nil
+	}
+
+	unquoted, rest, err := parseQuotedLocalPart(name)
+	if err != nil || rest != "" {
+		return "", fmt.Errorf("%w: bad quoted display name", ErrInvalidEmail)
+	}
+	return unquoted, nil
+}
+
+// isAtext reports whether c is an RFC 5322 atom character.
+func isAtext(c byte) bool {
+	return isLetter(c) || isDigit(c) || strings.IndexByte("!#$%&'*+-/=?^_`{|}~", c) >= 0
+}
+
+// isAtextRune extends isAtext with the non-ASCII characters RFC 6531
+// allows in atoms.
+func isAtextRune(r rune) bool {
+	if r < utf8.RuneSelf {
+		return isAtext(byte(r))
+	}
+	return unicode.IsGraphic(r) && !unicode.IsSpace(r)
+}
+
+func quoteLocalPart(local string) string {
+	needsQuotes := local == "" || strings.HasPrefix(local, ".") || strings.HasSuffix(local, ".") || strings.Contains(local, "..")
+	for _, r := range local {
+		if r != '.' && !isAtextRune(r) {
+			needsQuotes = true
+			break
+		}
+	}
+	if !needsQuotes {
+		return local
+	}
+	return quoteString(local)
+}
+
+func quoteDisplayName(name string) string {
+	if strings.ContainsAny(name, `"<>@,;:\.()[]`) {
+		return quoteString(name)
+	}
+	return name
+}
+
+func quoteString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -0,0 +1,216 @@
+package basics
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseEmailValid(t *testing.T) {
+	tests := []struct {
+		input  string
+		local  string
+		domain string
+	}{
+		{"john@example.com", "john", "example.com"},
+		{"first.last+tag@mail.example.co.uk", "first.last+tag", "mail.example.co.uk"},
+		{"o'brien@example.org", "o'brien", "example.org"},
+		{`"john doe"@example.com`, "john doe", "example.com"},
+		{`"a\"b"@example.com`, `a"b`, "example.com"},
+		{"user@[192.0.2.1]", "user", "[192.0.2.1]"},
+		{"user@[IPv6:2001:db8::1]", "user", "[IPv6:2001:db8::1]"},
+		{"  padded@example.com  ", "padded", "example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			addr, err := ParseEmail(tt.input)
+			if err != nil {
+				t.Fatalf("ParseEmail(%q) returned error: %v", tt.input, err)
+			}
+			if addr.LocalPart != tt.local || addr.Domain != tt.domain {
+				t.Errorf("ParseEmail(%q) = %q @ %q; want %q @ %q", tt.input, addr.LocalPart, addr.Domain, tt.local, tt.domain)
+			}
+		})
+	}
+}
+
+func TestParseEmailInvalid(t *testing.T) {
+	tests := []struct {
+		input string
+		want  error
+	}{
+		{"", ErrInvalidEmail},
+		{"plainaddress", ErrEmailMissingAt},
+		{"@example.com", ErrEmailLocalPart},
+		{".john@example.com", ErrEmailLocalPart},
+		{"john.@example.com", ErrEmailLocalPart},
+		{"john..doe@example.com", ErrEmailLocalPart},
+		{"john doe@example.com", ErrEmailLocalPart},
+		{`"unterminated@example.com`, ErrEmailLocalPart},
+		{"john@", ErrEmailDomain},
+		{"john@intranet", ErrEmailDomain},
+		{"john@example..com", ErrEmailDomain},
+		{"john@-example.com", ErrEmailDomain},
+		{"john@example.123", ErrEmailDomain},
+		{"john@exa_mple.com", ErrEmailDomain},
+		{"john@[300.0.0.1]", ErrEmailDomain},
+		{"john@[IPv6:nope]", ErrEmailDomain},
+		{strings.Repeat("a", 65) + "@example.com", ErrEmailLocalPart},
+		{"john@" + strings.Repeat("a", 63) + "." + strings.Repeat("b", 63) + "." + strings.Repeat("c", 63) + "." + strings.Repeat("d", 63) + ".com", ErrEmailTooLong},
+		{"John <john@example.com>", ErrEmailDisplayName},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := ParseEmail(tt.input)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("ParseEmail(%q) error = %v; want %v", tt.input, err, tt.want)
+			}
+			if !errors.Is(err, ErrInvalidEmail) {
+				t.Errorf("ParseEmail(%q) error = %v; want it to match ErrInvalidEmail", tt.input, err)
+			}
+		})
+	}
+}
+
+func TestEmailPolicyLenient(t *testing.T) {
+	policy := EmailPolicy{Mode: EmailLenient}
+
+	tests := []struct {
+		input   string
+		display string
+		address string
+	}{
+		{"jane@intranet", "", "jane@intranet"},
+		{"Jane Doe <jane@example.com>", "Jane Doe", "jane@example.com"},
+		{`"Doe, Jane" <jane@example.com>`, "Doe, Jane", "jane@example.com"},
+		{"<jane@example.com>", "", "jane@example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			addr, err := policy.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %v", tt.input, err)
+			}
+			if addr.DisplayName != tt.display || addr.Address() != tt.address {
+				t.Errorf("Parse(%q) = %q <%s>; want %q <%s>", tt.input, addr.DisplayName, addr.Address(), tt.display, tt.address)
+			}
+		})
+	}
+
+	if err := policy.Validate("Doe, Jane <jane@example.com>"); !errors.Is(err, ErrInvalidEmail) {
+		t.Errorf("unquoted comma in display name: error = %v; want ErrInvalidEmail", err)
+	}
+	if err := policy.Validate("john..doe@example.com"); !errors.Is(err, ErrEmailLocalPart) {
+		t.Errorf("lenient mode must still reject consecutive dots, got %v", err)
+	}
+}
+
+func TestEmailAddressString(t *testing.T) {
+	tests := []struct {
+		addr EmailAddress
+		want string
+	}{
+		{EmailAddress{LocalPart: "john", Domain: "example.com"}, "john@example.com"},
+		{EmailAddress{LocalPart: "john doe", Domain: "example.com"}, `"john doe"@example.com`},
+		{EmailAddress{LocalPart: `a"b`, Domain: "example.com"}, `"a\"b"@example.com`},
+		{EmailAddress{DisplayName: "Jane Doe", LocalPart: "jane", Domain: "example.com"}, "Jane Doe <jane@example.com>"},
+		{EmailAddress{DisplayName: "Doe, Jane", LocalPart: "jane", Domain: "example.com"}, `"Doe, Jane" <jane@example.com>`},
+	}
+
+	for _, tt := range tests {
+		if got := tt.addr.String(); got != tt.want {
+			t.Errorf("String() = %q; want %q", got, tt.want)
+		}
+	}
+}
+
+func TestEmailAddressRoundTrip(t *testing.T) {
+	policy := EmailPolicy{Mode: EmailLenient}
+	for _, input := range []string{`"john doe"@example.com`, `"Doe, Jane" <jane@example.com>`, "user@[192.0.2.1]"} {
+		addr, err := policy.Parse(input)
+		if err != nil {
+			t.Fatalf("Parse(%q) returned error: %v", input, err)
+		}
+		if got := addr.String(); got != input {
+			t.Errorf("Parse(%q).String() = %q", input, got)
+		}
+	}
+}
+
+func TestParseEmailInternational(t *testing.T) {
+	tests := []struct {
+		input string
+		ascii string
+	}{
+		{"用户@例子.广告", ""},
+		{"josé@bücher.de", ""},
+		{"jose@bücher.de", "jose@xn--bcher-kva.de"},
+		{"jose@xn--bcher-kva.de", "jose@xn--bcher-kva.de"},
+		{`"ü ber"@example.com`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			addr, err := ParseEmail(tt.input)
+			if err != nil {
+				t.Fatalf("ParseEmail(%q) returned error: %v", tt.input, err)
+			}
+			if !addr.IsInternational() && tt.input != "jose@xn--bcher-kva.de" {
+				t.Errorf("IsInternational() = false for %q", tt.input)
+			}
+
+			ascii, err := addr.ASCII()
+			if tt.ascii == "" {
+				if !errors.Is(err, ErrEmailNonASCII) {
+					t.Errorf("ASCII() error = %v; want ErrEmailNonASCII", err)
+				}
+				return
+			}
+			if err != nil || ascii != tt.ascii {
+				t.Errorf("ASCII() = %q, %v; want %q", ascii, err, tt.ascii)
+			}
+		})
+	}
+
+	if err := ValidateEmail("用户@例子.广告"); err != nil {
+		t.Errorf("ValidateEmail(Unicode address) returned error: %v", err)
+	}
+}
+
+func TestParseEmailInternationalInvalid(t *testing.T) {
+	tests := []struct {
+		input string
+		want  error
+	}{
+		{"用户@-例子.广告", ErrEmailDomain},
+		{"用户@例子.广 告", ErrEmailDomain},
+		{"user@xn--abc.com", ErrEmailDomain},
+		{"us\u200ber@example.com", ErrEmailLocalPart},
+		{"us\u00a0er@example.com", ErrEmailLocalPart},
+		{"user\xff@example.com", ErrInvalidEmail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if _, err := ParseEmail(tt.input); !errors.Is(err, tt.want) {
+				t.Errorf("ParseEmail(%q) error = %v; want %v", tt.input, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmailPolicyASCIIOnly(t *testing.T) {
+	policy := EmailPolicy{ASCIIOnly: true}
+
+	for _, input := range []string{"用户@example.com", "user@例子.广告", "josé@bücher.de"} {
+		if err := policy.Validate(input); !errors.Is(err, ErrEmailNonASCII) {
+			t.Errorf("Validate(%q) error = %v; want ErrEmailNonASCII", input, err)
+		}
+	}
+	if err := policy.Validate("jose@xn--bcher-kva.de"); err != nil {
+		t.Errorf("Validate(punycode domain) returned error: %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package basics
+
+import (
+	"strings"
+	"sync"
+)
+
+// EmailProviderRule describes how a mail provider maps addresses to
+// mailboxes.
+type EmailProviderRule struct {
+	// Domains served by the provider. Addresses at any of them are the
+	// same mailbox and canonicalize to the first one.
+	Domains []string
+	// PreserveCase keeps the local part's case. Most providers ignore it.
+	PreserveCase bool
+	// IgnoreDots removes dots from the local part, as Gmail does.
+	IgnoreDots bool
+	// TagSeparators lists characters that start a sub-address tag, such as
+	// "+" in "jane+news". The tag is removed.
+	TagSeparators string
+}
+
+// EmailCanonicalizer maps email addresses to a canonical form, so that
+// addresses reaching the same mailbox compare equal. Use the canonical form
+// as the uniqueness key when checking for duplicate accounts.
+type EmailCanonicalizer struct {
+	mu    sync.RWMutex
+	rules map[string]EmailProviderRule
+	// Default applies to domains without a rule.
+	Default EmailProviderRule
+}
+
+// NewEmailCanonicalizer returns a canonicalizer with the given provider
+// rules. Unknown domains only have their case normalized.
+func NewEmailCanonicalizer(rules ...EmailProviderRule) *EmailCanonicalizer {
+	c := &EmailCanonicalizer{rules: make(map[string]EmailProviderRule)}
+	for _, rule := range rules {
+		c.AddRule(rule)
+	}
+	return c
+}
+
+// AddRule adds a provider rule, replacing any existing rule for its
+// domains.
+func (c *EmailCanonicalizer) AddRule(rule EmailProviderRule) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, domain := range rule.Domains {
+		c.rules[normalizeRiskDomain(domain)] = rule
+	}
+}
+
+// DefaultEmailCanonicalizer knows the rules of the largest free providers.
+// Outlook, Hotmail and Live are separate mailboxes despite sharing a
+// provider, so they have separate rules.
+var DefaultEmailCanonicalizer = NewEmailCanonicalizer(
+	EmailProviderRule{Domains: []string{"gmail.com", "googlemail.com"}, IgnoreDots: true, TagSeparators: "+"},
+	EmailProviderRule{Domains: []string{"outlook.com"}, TagSeparators: "+"},
+	EmailProviderRule{Domains: []string{"hotmail.com"}, TagSeparators: "+"},
+	EmailProviderRule{Domains: []string{"live.com"}, TagSeparators: "+"},
+	EmailProviderRule{Domains: []string{"yahoo.com"}, TagSeparators: "-"},
+	EmailProviderRule{Domains: []string{"icloud.com", "me.com", "mac.com"}, TagSeparators: "+"},
+	EmailProviderRule{Domains: []string{"proton.me", "protonmail.com", "pm.me"}, TagSeparators: "+"},
+	EmailProviderRule{Domains: []string{"fastmail.com"}, TagSeparators: "+"},
+)
+
+// CanonicalEmail canonicalizes email with DefaultEmailCanonicalizer.
+func CanonicalEmail(email string) (string, error) {
+	return DefaultEmailCanonicalizer.Canonicalize(email)
+}
+
+// Canonicalize parses email leniently and returns its canonical addr-spec.
+// The domain is always lowercased and converted to punycode; the local part
+// is transformed by the provider's rule.
+func (c *EmailCanonicalizer) Canonicalize(email string) (string, error) {
+	addr, err := EmailPolicy{Mode: EmailLenient}.Parse(email)
+	if err != nil {
+		return "", err
+	}
+
+	domain := normalizeRiskDomain(addr.Domain)
+	c.mu.RLock()
+	rule, ok := c.rules[domain]
+	c.mu.RUnlock()
+	if !ok {
+		rule = c.Default
+	} else if len(rule.Domains) > 0 {
+		domain = normalizeRiskDomain(rule.Domains[0])
+	}
+
+	local := addr.LocalPart
+	if i := strings.IndexAny(local, rule.TagSeparators); rule.TagSeparators != "" && i > 0 {
+		local = local[:i]
+	}
+	if rule.IgnoreDots {
+		local = strings.ReplaceAll(local, ".", "")
+	}
+	if !rule.PreserveCase {
+		local = strings.ToLower(local)
+	}
+	return quoteLocalPart(local) + "@" + domain, nil
+}
+
+// SameMailbox reports whether a and b canonicalize to the same address.
+func (c *EmailCanonicalizer) SameMailbox(a, b string) (bool, error) {
+	ca, err := c.Canonicalize(a)
+	if err != nil {
+		return false, err
+	}
+	cb, err := c.Canonicalize(b)
+	if err != nil {
+		return false, err
+	}
+	return ca == cb, nil
+}
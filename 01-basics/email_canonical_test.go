@@ -0,0 +1,83 @@
+package basics
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCanonicalEmail(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"John.Doe+promo@gmail.com", "johndoe@gmail.com"},
+		{"johndoe@googlemail.com", "johndoe@gmail.com"},
+		{"J.O.H.N.D.O.E@GMAIL.COM", "johndoe@gmail.com"},
+		{"Jane+work@Outlook.com", "jane@outlook.com"},
+		{"jane.doe@outlook.com", "jane.doe@outlook.com"},
+		{"jane@hotmail.com", "jane@hotmail.com"},
+		{"jane-shopping@yahoo.com", "jane@yahoo.com"},
+		{"jane+tag@yahoo.com", "jane+tag@yahoo.com"},
+		{"Jane+x@me.com", "jane@icloud.com"},
+		{"Jane.Doe+x@Example.COM", "jane.doe+x@example.com"},
+		{"+tag@gmail.com", "+tag@gmail.com"},
+		{"Jane Doe <Jane.Doe@gmail.com>", "janedoe@gmail.com"},
+		{"jose@Bücher.de", "jose@xn--bcher-kva.de"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := CanonicalEmail(tt.input)
+			if err != nil {
+				t.Fatalf("CanonicalEmail(%q) returned error: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("CanonicalEmail(%q) = %q; want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := CanonicalEmail("not-an-email"); !errors.Is(err, ErrInvalidEmail) {
+		t.Errorf("CanonicalEmail(invalid) error = %v; want ErrInvalidEmail", err)
+	}
+}
+
+func TestEmailCanonicalizerCustomRules(t *testing.T) {
+	c := NewEmailCanonicalizer(
+		EmailProviderRule{Domains: []string{"corp.example", "mail.corp.example"}, TagSeparators: "+-"},
+		EmailProviderRule{Domains: []string{"legacy.example"}, PreserveCase: true},
+	)
+	c.Default = EmailProviderRule{PreserveCase: true}
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"Ann-list@mail.corp.example", "ann@corp.example"},
+		{"Ann+x@corp.example", "ann@corp.example"},
+		{"Ann.Lee@legacy.example", "Ann.Lee@legacy.example"},
+		{"Ann+x@other.example", "Ann+x@other.example"},
+		{"Ann.Lee@gmail.com", "Ann.Lee@gmail.com"},
+	}
+
+	for _, tt := range tests {
+		got, err := c.Canonicalize(tt.input)
+		if err != nil || got != tt.want {
+			t.Errorf("Canonicalize(%q) = %q, %v; want %q", tt.input, got, err, tt.want)
+		}
+	}
+}
+
+func TestSameMailbox(t *testing.T) {
+	same, err := DefaultEmailCanonicalizer.SameMailbox("John.Doe+promo@gmail.com", "johndoe@googlemail.com")
+	if err != nil || !same {
+		t.Errorf("SameMailbox(gmail variants) = %v, %v; want true", same, err)
+	}
+	same, err = DefaultEmailCanonicalizer.SameMailbox("jane@outlook.com", "jane@hotmail.com")
+	if err != nil || same {
+		t.Errorf("SameMailbox(outlook, hotmail) = %v, %v; want false", same, err)
+	}
+	if _, err := DefaultEmailCanonicalizer.SameMailbox("jane@gmail.com", "@"); err == nil {
+		t.Error("SameMailbox with an invalid address returned nil error")
+	}
+}
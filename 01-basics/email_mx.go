@@ -0,0 +1,124 @@
+package basics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+var (
+	ErrEmailUndeliverable    = errors.New("email domain does not accept mail")
+	ErrEmailDomainUnverified = errors.New("email domain could not be verified")
+)
+
+// Resolver performs the DNS lookups used by MXCheck. *net.Resolver
+// implements it; tests can substitute an in-memory implementation.
+type Resolver interface {
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// DefaultResolver is the resolver used when none is configured.
+var DefaultResolver Resolver = net.DefaultResolver
+
+// DefaultMXTimeout bounds each MXCheck lookup unless overridden.
+const DefaultMXTimeout = 5 * time.Second
+
+// MXCheck verifies that an email address's domain accepts mail. It is a
+// ContextRule[string].
+type MXCheck struct {
+	// Resolver performs lookups. Nil means DefaultResolver.
+	Resolver Resolver
+	// Timeout bounds the lookups. Zero means DefaultMXTimeout.
+	Timeout time.Duration
+	// RequireMX rejects domains without MX records. Otherwise a domain
+	// with an address record is accepted, as mail servers fall back to it.
+	RequireMX bool
+	// FailOpen accepts addresses when DNS fails or times out, rather than
+	// reporting ErrEmailDomainUnverified.
+	FailOpen bool
+}
+
+// DefaultMXCheck is used by ValidateUserContext.
+var DefaultMXCheck = MXCheck{}
+
+// ValidateContext fails with ErrEmailUndeliverable if the domain does not
+// exist, publishes a null MX record (RFC 7505), or has no mail server. The
+// address must already be syntactically valid; empty addresses and address
+// literals are left to other rules.
+func (c MXCheck) ValidateContext(ctx context.Context, email string) error {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return nil
+	}
+	addr, err := EmailPolicy{Mode: EmailLenient}.Parse(email)
+	if err != nil {
+		return err
+	}
+	if strings.HasPrefix(addr.Domain, "[") {
+		return nil
+	}
+	domain, err := DomainToASCII(addr.Domain)
+	if err != nil {
+		return err
+	}
+
+	resolver := c.Resolver
+	if resolver == nil {
+		resolver = DefaultResolver
+	}
+	timeout := c.Timeout
+	if timeout == 0 {
+		timeout = DefaultMXTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	undeliverable := &ParamError{
+		Err:     ErrEmailUndeliverable,
+		Params:  map[string]any{"domain": domain},
+		Message: fmt.Sprintf("%v (%s)", ErrEmailUndeliverable, domain),
+	}
+
+	records, err := resolver.LookupMX(ctx, domain+".")
+	switch {
+	case err == nil && len(records) == 1 && strings.TrimSuffix(records[0].Host, ".") == "":
+		return undeliverable
+	case err == nil && len(records) > 0:
+		return nil
+	case err != nil && !isNotFound(err):
+		return c.unverified(domain, err)
+	case c.RequireMX:
+		return undeliverable
+	}
+
+	hosts, err := resolver.LookupHost(ctx, domain+".")
+	switch {
+	case err == nil && len(hosts) > 0:
+		return nil
+	case err == nil || isNotFound(err):
+		return undeliverable
+	}
+	return c.unverified(domain, err)
+}
+
+func (c MXCheck) unverified(domain string, cause error) error {
+	if c.FailOpen {
+		return nil
+	}
+	return &ParamError{
+		Err:     fmt.Errorf("%w: %w", ErrEmailDomainUnverified, cause),
+		Params:  map[string]any{"domain": domain},
+		Message: fmt.Sprintf("%v (%s): %v", ErrEmailDomainUnverified, domain, cause),
+	}
+}
+
+// isNotFound reports whether err means the name has no records, as
+// opposed to the lookup failing.
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
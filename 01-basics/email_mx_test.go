@@ -0,0 +1,160 @@
+package basics
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// MockResolver is an in-memory Resolver. Names without records are
+// reported as not found.
+type MockResolver struct {
+	mu    sync.Mutex
+	mx    map[string][]*net.MX
+	hosts map[string][]string
+
+	// Track calls for verification
+	LookupMXCalls   []string
+	LookupHostCalls []string
+
+	// Configure behavior
+	LookupError error
+	// Delay makes lookups block until it passes or the context is done.
+	Delay time.Duration
+}
+
+func NewMockResolver() *MockResolver {
+	return &MockResolver{mx: make(map[string][]*net.MX), hosts: make(map[string][]string)}
+}
+
+func (m *MockResolver) AddMX(domain string, hosts ...string) *MockResolver {
+	for i, host := range hosts {
+		m.mx[domain] = append(m.mx[domain], &net.MX{Host: host, Pref: uint16(10 * (i + 1))})
+	}
+	return m
+}
+
+func (m *MockResolver) AddHost(domain string, addrs ...string) *MockResolver {
+	m.hosts[domain] = append(m.hosts[domain], addrs...)
+	return m
+}
+
+func (m *MockResolver) wait(ctx context.Context) error {
+	if m.Delay == 0 {
+		return nil
+	}
+	select {
+	case <-time.After(m.Delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (m *MockResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	m.mu.Lock()
+	m.LookupMXCalls = append(m.LookupMXCalls, name)
+	m.mu.Unlock()
+
+	if err := m.wait(ctx); err != nil {
+		return nil, err
+	}
+	if m.LookupError != nil {
+		return nil, m.LookupError
+	}
+	records, ok := m.mx[strings.TrimSuffix(name, ".")]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	return records, nil
+}
+
+func (m *MockResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	m.mu.Lock()
+	m.LookupHostCalls = append(m.LookupHostCalls, host)
+	m.mu.Unlock()
+
+	if err := m.wait(ctx); err != nil {
+		return nil, err
+	}
+	if m.LookupError != nil {
+		return nil, m.LookupError
+	}
+	addrs, ok := m.hosts[strings.TrimSuffix(host, ".")]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	return addrs, nil
+}
+
+func TestMXCheck(t *testing.T) {
+	resolver := NewMockResolver().
+		AddMX("example.com", "mx1.example.com.", "mx2.example.com.").
+		AddMX("nullmx.example", ".").
+		AddHost("implicit.example", "192.0.2.1").
+		AddMX("xn--bcher-kva.de", "mx.xn--bcher-kva.de.")
+	temporary := &net.DNSError{Err: "server misbehaving", Name: "example.com", IsTemporary: true}
+
+	tests := []struct {
+		name    string
+		check   MXCheck
+		email   string
+		wantErr error
+	}{
+		{"has MX", MXCheck{}, "jane@example.com", nil},
+		{"unicode domain", MXCheck{}, "jane@bücher.de", nil},
+		{"null MX", MXCheck{}, "jane@nullmx.example", ErrEmailUndeliverable},
+		{"missing domain", MXCheck{}, "jane@nowhere.example", ErrEmailUndeliverable},
+		{"implicit MX", MXCheck{}, "jane@implicit.example", nil},
+		{"implicit MX required", MXCheck{RequireMX: true}, "jane@implicit.example", ErrEmailUndeliverable},
+		{"address literal", MXCheck{}, "jane@[192.0.2.1]", nil},
+		{"empty", MXCheck{}, "", nil},
+		{"malformed", MXCheck{}, "jane", ErrInvalidEmail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.check.Resolver = resolver
+			err := tt.check.ValidateContext(context.Background(), tt.email)
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("ValidateContext(%q) error = %v; want nil", tt.email, err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateContext(%q) error = %v; want %v", tt.email, err, tt.wantErr)
+			}
+		})
+	}
+
+	failing := NewMockResolver()
+	failing.LookupError = temporary
+	err := MXCheck{Resolver: failing}.ValidateContext(context.Background(), "jane@example.com")
+	if !errors.Is(err, ErrEmailDomainUnverified) || !errors.Is(err, temporary) {
+		t.Errorf("lookup failure error = %v; want ErrEmailDomainUnverified wrapping the DNS error", err)
+	}
+	if err := (MXCheck{Resolver: failing, FailOpen: true}).ValidateContext(context.Background(), "jane@example.com"); err != nil {
+		t.Errorf("fail-open error = %v; want nil", err)
+	}
+}
+
+func TestMXCheckTimeout(t *testing.T) {
+	slow := NewMockResolver().AddMX("example.com", "mx.example.com.")
+	slow.Delay = time.Second
+
+	start := time.Now()
+	err := MXCheck{Resolver: slow, Timeout: 10 * time.Millisecond}.ValidateContext(context.Background(), "jane@example.com")
+	if !errors.Is(err, ErrEmailDomainUnverified) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v; want ErrEmailDomainUnverified wrapping context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("lookup took %v; want it cut short by the timeout", elapsed)
+	}
+
+	fe := NewFieldError("Email", "jane@example.com", err)
+	if fe.Code != "email.unverified" || fe.Params["domain"] != "example.com" {
+		t.Errorf("FieldError = {%s %v}; want email.unverified for example.com", fe.Code, fe.Params)
+	}
+}
@@ -0,0 +1,191 @@
+package basics
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Like the other specific email errors, these match ErrInvalidEmail with
+// errors.Is.
+var (
+	ErrEmailDisposable  = fmt.Errorf("%w: disposable addresses are not allowed", ErrInvalidEmail)
+	ErrEmailRoleAccount = fmt.Errorf("%w: role addresses are not allowed", ErrInvalidEmail)
+)
+
+var (
+	//go:embed data/disposable_domains.txt
+	defaultDisposableDomains string
+	//go:embed data/role_accounts.txt
+	defaultRoleAccounts string
+)
+
+// Score contributions of each risk signal. A verdict's score is their sum,
+// capped at 100.
+const (
+	disposableRiskScore  = 70
+	roleAccountRiskScore = 40
+)
+
+// EmailVerdict is the outcome of an email risk check.
+type EmailVerdict struct {
+	Disposable bool
+	// DisposableDomain is the listed domain that matched, which may be a
+	// parent of the address's domain.
+	DisposableDomain string
+	RoleAccount      bool
+	// Score ranges from 0 (no signals) to 100. Callers may use it as a soft
+	// signal instead of rejecting addresses outright.
+	Score int
+}
+
+// Risky reports whether any signal was found.
+func (v EmailVerdict) Risky() bool {
+	return v.Score > 0
+}
+
+// EmailRiskChecker flags addresses at disposable email providers and role
+// addresses such as noreply@ or postmaster@.
+type EmailRiskChecker struct {
+	mu         sync.RWMutex
+	disposable map[string]bool
+	roles      map[string]bool
+}
+
+func NewEmailRiskChecker() *EmailRiskChecker {
+	return &EmailRiskChecker{disposable: make(map[string]bool), roles: make(map[string]bool)}
+}
+
+// DefaultEmailRiskChecker uses the built-in domain and role lists.
+var DefaultEmailRiskChecker = func() *EmailRiskChecker {
+	c := NewEmailRiskChecker()
+	// The embedded files are known to be readable.
+	_ = c.LoadDisposableDomains(strings.NewReader(defaultDisposableDomains))
+	_ = c.LoadRoleAccounts(strings.NewReader(defaultRoleAccounts))
+	return c
+}()
+
+// AddDisposableDomains lists domains, and implicitly all their subdomains,
+// as disposable.
+func (c *EmailRiskChecker) AddDisposableDomains(domains ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, d := range domains {
+		c.disposable[normalizeRiskDomain(d)] = true
+	}
+}
+
+// AddRoleAccounts lists local parts that identify role addresses.
+func (c *EmailRiskChecker) AddRoleAccounts(localParts ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, l := range localParts {
+		c.roles[normalizeRoleLocalPart(l)] = true
+	}
+}
+
+// LoadDisposableDomains reads domains one per line, skipping blank lines
+// and "#" comments.
+func (c *EmailRiskChecker) LoadDisposableDomains(r io.Reader) error {
+	domains, err := readTerms(r)
+	if err != nil {
+		return err
+	}
+	c.AddDisposableDomains(domains...)
+	return nil
+}
+
+// LoadRoleAccounts reads role local parts in the same format as
+// LoadDisposableDomains.
+func (c *EmailRiskChecker) LoadRoleAccounts(r io.Reader) error {
+	locals, err := readTerms(r)
+	if err != nil {
+		return err
+	}
+	c.AddRoleAccounts(locals...)
+	return nil
+}
+
+// Check parses email leniently and assesses it.
+func (c *EmailRiskChecker) Check(email string) (EmailVerdict, error) {
+	addr, err := EmailPolicy{Mode: EmailLenient}.Parse(email)
+	if err != nil {
+		return EmailVerdict{}, err
+	}
+	return c.CheckAddress(addr), nil
+}
+
+// CheckAddress assesses an already parsed address.
+func (c *EmailRiskChecker) CheckAddress(addr *EmailAddress) EmailVerdict {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var v EmailVerdict
+	domain := normalizeRiskDomain(addr.Domain)
+	for {
+		if c.disposable[domain] {
+			v.Disposable, v.DisposableDomain = true, domain
+			v.Score += disposableRiskScore
+			break
+		}
+		_, parent, ok := strings.Cut(domain, ".")
+		if !ok {
+			break
+		}
+		domain = parent
+	}
+
+	local, _, _ := strings.Cut(addr.LocalPart, "+")
+	if c.roles[normalizeRoleLocalPart(local)] {
+		v.RoleAccount = true
+		v.Score += roleAccountRiskScore
+	}
+
+	v.Score = min(v.Score, 100)
+	return v
+}
+
+// normalizeRiskDomain lowercases a domain and converts it to punycode, so
+// that Unicode and ASCII spellings of a listed domain both match.
+func normalizeRiskDomain(domain string) string {
+	domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+	if ascii, err := DomainToASCII(domain); err == nil {
+		return ascii
+	}
+	return domain
+}
+
+func normalizeRoleLocalPart(local string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(".-_", r) {
+			return -1
+		}
+		return r
+	}, strings.ToLower(strings.TrimSpace(local)))
+}
+
+// riskError turns a verdict into a hard failure according to the policy.
+func (p EmailPolicy) riskError(addr *EmailAddress) error {
+	if !p.RejectDisposable && !p.RejectRoleAccounts {
+		return nil
+	}
+	checker := p.Risk
+	if checker == nil {
+		checker = DefaultEmailRiskChecker
+	}
+
+	v := checker.CheckAddress(addr)
+	switch {
+	case p.RejectDisposable && v.Disposable:
+		return &ParamError{
+			Err:     ErrEmailDisposable,
+			Params:  map[string]any{"domain": v.DisposableDomain},
+			Message: fmt.Sprintf("%v (%s)", ErrEmailDisposable, v.DisposableDomain),
+		}
+	case p.RejectRoleAccounts && v.RoleAccount:
+		return ErrEmailRoleAccount
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package basics
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEmailRiskCheckerDefault(t *testing.T) {
+	tests := []struct {
+		email      string
+		disposable string
+		role       bool
+		score      int
+	}{
+		{"jane@example.com", "", false, 0},
+		{"jane@mailinator.com", "mailinator.com", false, 70},
+		{"jane@eu.MAILINATOR.com", "mailinator.com", false, 70},
+		{"jane@notmailinator.com", "", false, 0},
+		{"noreply@example.com", "", true, 40},
+		{"No-Reply@example.com", "", true, 40},
+		{"postmaster+bounces@example.com", "", true, 40},
+		{"support@yopmail.com", "yopmail.com", true, 100},
+		{"Jane <info@example.com>", "", true, 40},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			v, err := DefaultEmailRiskChecker.Check(tt.email)
+			if err != nil {
+				t.Fatalf("Check(%q) returned error: %v", tt.email, err)
+			}
+			if v.Disposable != (tt.disposable != "") || v.DisposableDomain != tt.disposable {
+				t.Errorf("Disposable = %v (%q); want %q", v.Disposable, v.DisposableDomain, tt.disposable)
+			}
+			if v.RoleAccount != tt.role {
+				t.Errorf("RoleAccount = %v; want %v", v.RoleAccount, tt.role)
+			}
+			if v.Score != tt.score || v.Risky() != (tt.score > 0) {
+				t.Errorf("Score = %d, Risky = %v; want %d", v.Score, v.Risky(), tt.score)
+			}
+		})
+	}
+
+	if _, err := DefaultEmailRiskChecker.Check("not-an-email"); !errors.Is(err, ErrInvalidEmail) {
+		t.Errorf("Check(invalid) error = %v; want ErrInvalidEmail", err)
+	}
+}
+
+func TestEmailRiskCheckerCustomLists(t *testing.T) {
+	c := NewEmailRiskChecker()
+	if err := c.LoadDisposableDomains(strings.NewReader("# test\nthrowaway.test\nbücher-temp.de\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.LoadRoleAccounts(strings.NewReader("ops\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		email string
+		risky bool
+	}{
+		{"a@x.throwaway.test", true},
+		{"a@xn--bcher-temp-9db.de", true},
+		{"a@bücher-temp.de", true},
+		{"ops@example.com", true},
+		{"o.p.s@example.com", true},
+		{"noreply@example.com", false},
+		{"a@mailinator.com", false},
+	}
+
+	for _, tt := range tests {
+		v, err := c.Check(tt.email)
+		if err != nil {
+			t.Fatalf("Check(%q) returned error: %v", tt.email, err)
+		}
+		if v.Risky() != tt.risky {
+			t.Errorf("Check(%q) = %+v; want risky %v", tt.email, v, tt.risky)
+		}
+	}
+}
+
+func TestEmailPolicyRiskHardFailure(t *testing.T) {
+	policy := EmailPolicy{RejectDisposable: true}
+
+	err := policy.Validate("jane@guerrillamail.com")
+	if !errors.Is(err, ErrEmailDisposable) || !errors.Is(err, ErrInvalidEmail) {
+		t.Fatalf("Validate(disposable) error = %v; want ErrEmailDisposable wrapping ErrInvalidEmail", err)
+	}
+	if fe := NewFieldError("Email", "", err); fe.Code != "email.disposable" || fe.Params["domain"] != "guerrillamail.com" {
+		t.Errorf("FieldError = {%s %v}", fe.Code, fe.Params)
+	}
+	if err := policy.Validate("noreply@example.com"); err != nil {
+		t.Errorf("role address rejected without RejectRoleAccounts: %v", err)
+	}
+
+	policy.RejectRoleAccounts = true
+	err = policy.Validate("noreply@example.com")
+	if !errors.Is(err, ErrEmailRoleAccount) || !errors.Is(err, ErrInvalidEmail) {
+		t.Errorf("Validate(role) error = %v; want ErrEmailRoleAccount wrapping ErrInvalidEmail", err)
+	}
+	if fe := NewFieldError("Email", "", err); fe.Code != "email.role_account" {
+		t.Errorf("FieldError code = %s; want email.role_account", fe.Code)
+	}
+
+	policy.Risk = NewEmailRiskChecker()
+	if err := policy.Validate("jane@guerrillamail.com"); err != nil {
+		t.Errorf("Validate with empty custom checker returned error: %v", err)
+	}
+
+	if err := ValidateEmail("jane@mailinator.com"); err != nil {
+		t.Errorf("ValidateEmail should treat risk as a soft signal by default, got %v", err)
+	}
+}
@@ -0,0 +1,298 @@
+package basics
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	ErrRateNotFound = errors.New("exchange rate not found")
+	ErrInvalidRate  = errors.New("invalid exchange rate")
+)
+
+const rateDateLayout = "2006-01-02"
+
+// ExchangeRate is the value of one unit of From in To, valid from AsOf.
+type ExchangeRate struct {
+	From string
+	To   string
+	Rate *big.Rat
+	AsOf time.Time
+}
+
+type currencyPair struct {
+	from, to string
+}
+
+// Converter converts Money between currencies using a local table of
+// dated exchange rates. Rates are exact decimals, so conversions only round
+// once, to the target currency's minor units.
+type Converter struct {
+	rates map[currencyPair][]ExchangeRate // sorted by AsOf
+}
+
+func NewConverter(rates ...ExchangeRate) (*Converter, error) {
+	c := &Converter{rates: make(map[currencyPair][]ExchangeRate)}
+	for _, r := range rates {
+		if err := c.Add(r); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func (c *Converter) Add(r ExchangeRate) error {
+	from, err := LookupCurrency(r.From)
+	if err != nil {
+		return err
+	}
+	to, err := LookupCurrency(r.To)
+	if err != nil {
+		return err
+	}
+	if r.Rate == nil || r.Rate.Sign() <= 0 {
+		return fmt.Errorf("%w: %s/%s must be positive", ErrInvalidRate, from.Code, to.Code)
+	}
+
+	r.From, r.To = from.Code, to.Code
+	key := currencyPair{from.Code, to.Code}
+	list := append(c.rates[key], r)
+	slices.SortStableFunc(list, func(a, b ExchangeRate) int { return a.AsOf.Compare(b.AsOf) })
+	c.rates[key] = list
+	return nil
+}
+
+// LoadRatesFile reads rates from a .json or .csv file.
+func LoadRatesFile(path string) (*Converter, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return LoadRatesJSON(f)
+	case ".csv":
+		return LoadRatesCSV(f)
+	}
+	return nil, fmt.Errorf("unsupported rate file format: %s", path)
+}
+
+// LoadRatesJSON reads rates in the form
+//
+//	{"rates": [{"from": "EUR", "to": "USD", "rate": "1.0856", "date": "2024-05-01"}]}
+//
+// Rates may be given as JSON strings or numbers.
+func LoadRatesJSON(r io.Reader) (*Converter, error) {
+	var doc struct {
+		Rates []struct {
+			From string      `json:"from"`
+			To   string      `json:"to"`
+			Rate json.Number `json:"rate"`
+			Date string      `json:"date"`
+		} `json:"rates"`
+	}
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidRate, err)
+	}
+
+	c, _ := NewConverter()
+	for i, entry := range doc.Rates {
+		rate, err := parseRateRecord(entry.From, entry.To, entry.Rate.String(), entry.Date)
+		if err == nil {
+			err = c.Add(rate)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("rate %d: %w", i, err)
+		}
+	}
+	return c, nil
+}
+
+// LoadRatesCSV reads rates from CSV with a from,to,rate,date header.
+func LoadRatesCSV(r io.Reader) (*Converter, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 4
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidRate, err)
+	}
+	if strings.ToLower(strings.Join(header, ",")) != "from,to,rate,date" {
+		return nil, fmt.Errorf("%w: header must be from,to,rate,date", ErrInvalidRate)
+	}
+
+	c, _ := NewConverter()
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return c, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidRate, err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		rate, err := parseRateRecord(record[0], record[1], record[2], record[3])
+		if err == nil {
+			err = c.Add(rate)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+}
+
+func parseRateRecord(from, to, rate, date string) (ExchangeRate, error) {
+	value, ok := new(big.Rat).SetString(strings.TrimSpace(rate))
+	if !ok {
+		return ExchangeRate{}, fmt.Errorf("%w: %q", ErrInvalidRate, rate)
+	}
+	asOf, err := time.Parse(rateDateLayout, strings.TrimSpace(date))
+	if err != nil {
+		return ExchangeRate{}, fmt.Errorf("%w: bad date %q", ErrInvalidRate, date)
+	}
+	return ExchangeRate{From: from, To: to, Rate: value, AsOf: asOf}, nil
+}
+
+// Rate returns the rate from one currency to another in effect on the given
+// date. It uses a direct rate, the inverse of the opposite rate, or a cross
+// rate through a common third currency, in that order of preference. The
+// returned AsOf is the oldest date among the rates used.
+func (c *Converter) Rate(from, to string, on time.Time) (ExchangeRate, error) {
+	fromCur, err := LookupCurrency(from)
+	if err != nil {
+		return ExchangeRate{}, err
+	}
+	toCur, err := LookupCurrency(to)
+	if err != nil {
+		return ExchangeRate{}, err
+	}
+	from, to = fromCur.Code, toCur.Code
+
+	if from == to {
+		return ExchangeRate{From: from, To: to, Rate: big.NewRat(1, 1), AsOf: on}, nil
+	}
+	if r, ok := c.directOrInverse(from, to, on); ok {
+		return r, nil
+	}
+
+	pivots := make(map[string]bool)
+	for pair := range c.rates {
+		for _, code := range []string{pair.from, pair.to} {
+			if code != from && code != to {
+				pivots[code] = true
+			}
+		}
+	}
+	candidates := make([]string, 0, len(pivots))
+	for code := range pivots {
+		candidates = append(candidates, code)
+	}
+	slices.Sort(candidates)
+
+	for _, pivot := range candidates {
+		first, ok := c.directOrInverse(from, pivot, on)
+		if !ok {
+			continue
+		}
+		second, ok := c.directOrInverse(pivot, to, on)
+		if !ok {
+			continue
+		}
+
+		asOf := first.AsOf
+		if second.AsOf.Before(asOf) {
+			asOf = second.AsOf
+		}
+		return ExchangeRate{
+			From: from,
+			To:   to,
+			Rate: new(big.Rat).Mul(first.Rate, second.Rate),
+			AsOf: asOf,
+		}, nil
+	}
+
+	return ExchangeRate{}, fmt.Errorf("%w: %s to %s on %s", ErrRateNotFound, from, to, on.Format(rateDateLayout))
+}
+
+func (c *Converter) directOrInverse(from, to string, on time.Time) (ExchangeRate, bool) {
+	if r, ok := c.latest(from, to, on); ok {
+		return r, true
+	}
+	if r, ok := c.latest(to, from, on); ok {
+		return ExchangeRate{From: from, To: to, Rate: new(big.Rat).Inv(r.Rate), AsOf: r.AsOf}, true
+	}
+	return ExchangeRate{}, false
+}
+
+// latest returns the most recent rate for the pair dated on or before on.
+func (c *Converter) latest(from, to string, on time.Time) (ExchangeRate, bool) {
+	list := c.rates[currencyPair{from, to}]
+	for i := len(list) - 1; i >= 0; i-- {
+		if !list[i].AsOf.After(on) {
+			return list[i], true
+		}
+	}
+	return ExchangeRate{}, false
+}
+
+// Convert converts m into the target currency using the rate in effect on
+// the given date. The result is rounded half to even to the target
+// currency's minor units.
+func (c *Converter) Convert(m Money, to string, on time.Time) (Money, error) {
+	rate, err := c.Rate(m.currency.Code, to, on)
+	if err != nil {
+		return Money{}, err
+	}
+	target, _ := LookupCurrency(rate.To)
+
+	// minor_to = minor_from * rate * 10^(units_to - units_from)
+	value := new(big.Rat).SetInt64(m.minor)
+	value.Mul(value, rate.Rate)
+	if shift := target.MinorUnits - m.currency.MinorUnits; shift >= 0 {
+		value.Mul(value, pow10Rat(shift))
+	} else {
+		value.Quo(value, pow10Rat(-shift))
+	}
+
+	minor := roundHalfEven(value)
+	if !minor.IsInt64() {
+		return Money{}, ErrAmountOverflow
+	}
+	return Money{minor: minor.Int64(), currency: target}, nil
+}
+
+func pow10Rat(n int) *big.Rat {
+	return new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil))
+}
+
+func roundHalfEven(r *big.Rat) *big.Int {
+	quo, rem := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if rem.Sign() == 0 {
+		return quo
+	}
+
+	// Compare 2·|rem| with the denominator to find which side of .5 we are.
+	twice := new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2))
+	cmp := twice.Cmp(r.Denom())
+	if cmp > 0 || (cmp == 0 && quo.Bit(0) == 1) {
+		if r.Sign() < 0 {
+			quo.Sub(quo, big.NewInt(1))
+		} else {
+			quo.Add(quo, big.NewInt(1))
+		}
+	}
+	return quo
+}
@@ -0,0 +1,155 @@
+package basics
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testRatesJSON = `{
+  "rates": [
+    {"from": "EUR", "to": "USD", "rate": "1.08", "date": "2024-01-01"},
+    {"from": "EUR", "to": "USD", "rate": "1.10", "date": "2024-02-01"},
+    {"from": "EUR", "to": "JPY", "rate": 160, "date": "2024-01-15"},
+    {"from": "USD", "to": "KWD", "rate": "0.3075", "date": "2024-01-01"}
+  ]
+}`
+
+func mustDate(s string) time.Time {
+	d, err := time.Parse(rateDateLayout, s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestConverterConvert(t *testing.T) {
+	conv, err := LoadRatesJSON(strings.NewReader(testRatesJSON))
+	if err != nil {
+		t.Fatalf("LoadRatesJSON: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		amount string
+		from   string
+		to     string
+		on     string
+		want   string
+	}{
+		{"direct rate", "100.00", "EUR", "USD", "2024-01-20", "108.00 USD"},
+		{"newer rate applies", "100.00", "EUR", "USD", "2024-03-01", "110.00 USD"},
+		{"inverse rate", "110.00", "USD", "EUR", "2024-02-01", "100.00 EUR"},
+		{"to zero minor units", "10.00", "EUR", "JPY", "2024-02-01", "1600 JPY"},
+		{"cross rate via EUR", "1080", "JPY", "USD", "2024-01-20", "7.29 USD"},
+		{"half to even", "0.25", "EUR", "USD", "2024-02-01", "0.28 USD"},
+		{"three minor units", "10.00", "USD", "KWD", "2024-01-01", "3.075 KWD"},
+		{"same currency", "5.00", "EUR", "EUR", "2023-01-01", "5.00 EUR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mustParseMoney(t, tt.amount, tt.from)
+			got, err := conv.Convert(m, tt.to, mustDate(tt.on))
+			if err != nil {
+				t.Fatalf("Convert: %v", err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("Convert(%s, %s) = %s; want %s", m, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConverterRateAsOf(t *testing.T) {
+	conv, _ := LoadRatesJSON(strings.NewReader(testRatesJSON))
+
+	rate, err := conv.Rate("JPY", "USD", mustDate("2024-03-01"))
+	if err != nil {
+		t.Fatalf("Rate: %v", err)
+	}
+	if !rate.AsOf.Equal(mustDate("2024-01-15")) {
+		t.Errorf("cross rate AsOf = %s; want oldest leg 2024-01-15", rate.AsOf.Format(rateDateLayout))
+	}
+}
+
+func TestConverterMissingRate(t *testing.T) {
+	conv, _ := LoadRatesJSON(strings.NewReader(testRatesJSON))
+
+	tests := []struct {
+		name string
+		from string
+		to   string
+		on   string
+	}{
+		{"no pair", "EUR", "GBP", "2024-02-01"},
+		{"before first rate", "EUR", "USD", "2023-12-31"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := conv.Convert(mustParseMoney(t, "1", tt.from), tt.to, mustDate(tt.on))
+			if !errors.Is(err, ErrRateNotFound) {
+				t.Errorf("error = %v; want ErrRateNotFound", err)
+			}
+		})
+	}
+}
+
+func TestLoadRatesFile(t *testing.T) {
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "rates.csv")
+	csvData := "from,to,rate,date\nGBP,USD,1.25,2024-01-01\n"
+	if err := os.WriteFile(csvPath, []byte(csvData), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	conv, err := LoadRatesFile(csvPath)
+	if err != nil {
+		t.Fatalf("LoadRatesFile: %v", err)
+	}
+	got, err := conv.Convert(mustParseMoney(t, "2", "GBP"), "USD", mustDate("2024-06-01"))
+	if err != nil || got.String() != "2.50 USD" {
+		t.Errorf("Convert = %v, %v; want 2.50 USD", got, err)
+	}
+}
+
+func TestLoadRatesInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		load    func() error
+		wantErr error
+	}{
+		{"bad csv header", func() error {
+			_, err := LoadRatesCSV(strings.NewReader("a,b,c,d\n"))
+			return err
+		}, ErrInvalidRate},
+		{"bad csv rate", func() error {
+			_, err := LoadRatesCSV(strings.NewReader("from,to,rate,date\nEUR,USD,abc,2024-01-01\n"))
+			return err
+		}, ErrInvalidRate},
+		{"negative rate", func() error {
+			_, err := LoadRatesCSV(strings.NewReader("from,to,rate,date\nEUR,USD,-1,2024-01-01\n"))
+			return err
+		}, ErrInvalidRate},
+		{"bad date", func() error {
+			_, err := LoadRatesJSON(strings.NewReader(`{"rates":[{"from":"EUR","to":"USD","rate":1,"date":"01/02/2024"}]}`))
+			return err
+		}, ErrInvalidRate},
+		{"unknown currency", func() error {
+			_, err := LoadRatesJSON(strings.NewReader(`{"rates":[{"from":"EUR","to":"XXX","rate":1,"date":"2024-01-01"}]}`))
+			return err
+		}, ErrUnknownCurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.load(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v; want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,692 @@
+package basics
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidExpression  = errors.New("invalid expression")
+	ErrUnknownFunction    = errors.New("unknown function")
+	ErrUnsupportedOperand = errors.New("unsupported operand types")
+	ErrNegativeExponent   = errors.New("exponent must not be negative")
+	ErrPowerOverflow      = errors.New("power is too large")
+)
+
+// maxComplexExponent bounds integer exponents of complex powers, which are
+// converted from float64.
+const maxComplexExponent = 1 << 31
+
+// Evaluate parses and evaluates an arithmetic expression using the
+// calculator's operations. The result is an int, a complex128 or a Matrix.
+//
+// Supported syntax:
+//   - integers (42), reals (2.5) and imaginary numbers (3i, i)
+//   - matrices ([[1, 2], [3, 4]]) and row vectors ([1, 2])
+//   - + - * / ^, unary minus and parentheses
+//   - det(m), inv(m), transpose(m), solve(a, b) and conj(z)
+//
+// Integer operands use integer arithmetic; as soon as a real or imaginary
+// number is involved the computation is carried out in complex128.
+func (c *Calculator) Evaluate(expr string) (any, error) {
+	root, err := parseExpression(expr)
+	if err != nil {
+		return nil, err
+	}
+	return c.eval(root)
+}
+
+type exprNode interface{}
+
+type literalNode struct {
+	value any
+}
+
+type negateNode struct {
+	operand exprNode
+}
+
+type binaryNode struct {
+	op          byte
+	left, right exprNode
+}
+
+type callNode struct {
+	name string
+	args []exprNode
+}
+
+func (c *Calculator) eval(n exprNode) (any, error) {
+	if lit, ok := n.(literalNode); ok {
+		return lit.value, nil
+	}
+
+	children := childNodes(n)
+	args := make([]any, len(children))
+	for i, child := range children {
+		v, err := c.eval(child)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+
+	v, _, err := c.apply(n, args)
+	return v, err
+}
+
+func childNodes(n exprNode) []exprNode {
+	switch n := n.(type) {
+	case negateNode:
+		return []exprNode{n.operand}
+	case binaryNode:
+		return []exprNode{n.left, n.right}
+	case callNode:
+		return n.args
+	}
+	return nil
+}
+
+// apply performs the operation of a non-literal node on its already
+// evaluated operands. It also reports the name of the Calculator method
+// that did the work.
+func (c *Calculator) apply(n exprNode, args []any) (any, string, error) {
+	switch n := n.(type) {
+	case negateNode:
+		return c.negate(args[0])
+	case binaryNode:
+		return c.applyBinary(n.op, args[0], args[1])
+	case callNode:
+		return c.applyFunction(n.name, args)
+	}
+	return nil, "", ErrInvalidExpression
+}
+
+func (c *Calculator) negate(v any) (any, string, error) {
+	switch v := v.(type) {
+	case int:
+		return c.Substract(0, v), "Substract", nil
+	case complex128:
+		return c.SubtractComplex(0, v), "SubtractComplex", nil
+	case Matrix:
+		m, err := c.MatrixScale(v, -1)
+		return m, "MatrixScale", err
+	}
+	return nil, "", fmt.Errorf("%w: -%s", ErrUnsupportedOperand, valueKind(v))
+}
+
+func (c *Calculator) applyBinary(op byte, left, right any) (any, string, error) {
+	if a, ok := left.(int); ok {
+		if b, ok := right.(int); ok {
+			return c.applyInt(op, a, b)
+		}
+	}
+
+	if a, ok := asComplex(left); ok {
+		if b, ok := asComplex(right); ok {
+			return c.applyComplex(op, a, b)
+		}
+	}
+
+	if a, ok := left.(Matrix); ok {
+		if b, ok := right.(Matrix); ok {
+			switch op {
+			case '+':
+				m, err := c.MatrixAdd(a, b)
+				return m, "MatrixAdd", err
+			case '-':
+				m, err := c.MatrixSubtract(a, b)
+				return m, "MatrixSubtract", err
+			case '*':
+				m, err := c.MatrixMultiply(a, b)
+				return m, "MatrixMultiply", err
+			}
+		} else if s, ok := asReal(right); ok {
+			switch op {
+			case '*':
+				m, err := c.MatrixScale(a, s)
+				return m, "MatrixScale", err
+			case '/':
+				if s == 0 {
+					return nil, "MatrixScale", ErrDivisionByZero
+				}
+				m, err := c.MatrixScale(a, 1/s)
+				return m, "MatrixScale", err
+			}
+		}
+	} else if b, ok := right.(Matrix); ok && op == '*' {
+		if s, ok := asReal(left); ok {
+			m, err := c.MatrixScale(b, s)
+			return m, "MatrixScale", err
+		}
+	}
+
+	return nil, "", fmt.Errorf("%w: %s %c %s", ErrUnsupportedOperand, valueKind(left), op, valueKind(right))
+}
+
+func (c *Calculator) applyInt(op byte, a, b int) (any, string, error) {
+	switch op {
+	case '+':
+		return c.Add(a, b), "Add", nil
+	case '-':
+		return c.Substract(a, b), "Substract", nil
+	case '*':
+		return c.Multiply(a, b), "Multiply", nil
+	case '/':
+		v, err := c.Divide(a, b)
+		return v, "Divide", err
+	case '^':
+		if b < 0 {
+			return nil, "Power", ErrNegativeExponent
+		}
+		if !powerFits(a, b) {
+			return nil, "Power", fmt.Errorf("%w: %d^%d overflows an int", ErrPowerOverflow, a, b)
+		}
+		return c.Power(a, b), "Power", nil
+	}
+	return nil, "", ErrInvalidExpression
+}
+
+func (c *Calculator) applyComplex(op byte, a, b complex128) (any, string, error) {
+	switch op {
+	case '+':
+		return c.AddComplex(a, b), "AddComplex", nil
+	case '-':
+		return c.SubtractComplex(a, b), "SubtractComplex", nil
+	case '*':
+		return c.MultiplyComplex(a, b), "MultiplyComplex", nil
+	case '/':
+		v, err := c.DivideComplex(a, b)
+		return v, "DivideComplex", err
+	case '^':
+		if imag(b) != 0 || real(b) != math.Trunc(real(b)) {
+			return nil, "PowerComplex", fmt.Errorf("%w: exponent must be an integer", ErrUnsupportedOperand)
+		}
+		if math.Abs(real(b)) > maxComplexExponent {
+			return nil, "PowerComplex", fmt.Errorf("%w: exponent %g is out of range", ErrPowerOverflow, real(b))
+		}
+		v, err := c.PowerComplex(a, int(real(b)))
+		return v, "PowerComplex", err
+	}
+	return nil, "", ErrInvalidExpression
+}
+
+// powerFits reports whether base^exp, for exp >= 0, fits in an int.
+func powerFits(base, exp int) bool {
+	if base >= -1 && base <= 1 {
+		return true
+	}
+	// |result| at least doubles each step, so this overflows, and stops,
+	// within 64 iterations.
+	result := 1
+	for i := 0; i < exp; i++ {
+		next := result * base
+		if next/base != result {
+			return false
+		}
+		result = next
+	}
+	return true
+}
+
+var functionArity = map[string]int{"det": 1, "inv": 1, "transpose": 1, "conj": 1, "solve": 2}
+
+func (c *Calculator) applyFunction(name string, args []any) (any, string, error) {
+	want, ok := functionArity[name]
+	if !ok {
+		return nil, "", fmt.Errorf("%w: %s", ErrUnknownFunction, name)
+	}
+	if len(args) != want {
+		return nil, "", fmt.Errorf("%w: %s expects %d argument(s), got %d", ErrInvalidExpression, name, want, len(args))
+	}
+
+	if name == "conj" {
+		z, ok := asComplex(args[0])
+		if !ok {
+			return nil, "Conjugate", fmt.Errorf("%w: conj(%s)", ErrUnsupportedOperand, valueKind(args[0]))
+		}
+		return c.Conjugate(z), "Conjugate", nil
+	}
+
+	m, ok := args[0].(Matrix)
+	if !ok {
+		return nil, "", fmt.Errorf("%w: %s(%s)", ErrUnsupportedOperand, name, valueKind(args[0]))
+	}
+
+	switch name {
+	case "det":
+		det, err := c.Determinant(m)
+		if err != nil {
+			return nil, "Determinant", err
+		}
+		return complex(det, 0), "Determinant", nil
+	case "inv":
+		inv, err := c.Inverse(m)
+		return inv, "Inverse", err
+	case "transpose":
+		t, err := c.Transpose(m)
+		return t, "Transpose", err
+	}
+
+	b, ok := args[1].(Matrix)
+	if !ok {
+		return nil, "Solve", fmt.Errorf("%w: solve(matrix, %s)", ErrUnsupportedOperand, valueKind(args[1]))
+	}
+	vector, column, err := flattenVector(b)
+	if err != nil {
+		return nil, "Solve", err
+	}
+	x, err := c.Solve(m, vector)
+	if err != nil {
+		return nil, "Solve", err
+	}
+	if column {
+		out := newMatrix(len(x), 1)
+		for i, v := range x {
+			out[i][0] = v
+		}
+		return out, "Solve", nil
+	}
+	return Matrix{x}, "Solve", nil
+}
+
+// flattenVector accepts a row (1×n) or column (n×1) matrix and returns its
+// entries, reporting whether it was a column.
+func flattenVector(m Matrix) ([]float64, bool, error) {
+	rows, cols, err := m.Dims()
+	if err != nil {
+		return nil, false, err
+	}
+	switch {
+	case rows == 1:
+		return append([]float64(nil), m[0]...), false, nil
+	case cols == 1:
+		out := make([]float64, rows)
+		for i := range m {
+			out[i] = m[i][0]
+		}
+		return out, true, nil
+	}
+	return nil, false, ErrDimensionMismatch
+}
+
+func asComplex(v any) (complex128, bool) {
+	switch v := v.(type) {
+	case int:
+		return complex(float64(v), 0), true
+	case complex128:
+		return v, true
+	}
+	return 0, false
+}
+
+func asReal(v any) (float64, bool) {
+	z, ok := asComplex(v)
+	if !ok || imag(z) != 0 {
+		return 0, false
+	}
+	return real(z), true
+}
+
+func valueKind(v any) string {
+	switch v.(type) {
+	case int:
+		return "integer"
+	case complex128:
+		return "complex"
+	case Matrix:
+		return "matrix"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// FormatValue renders a value returned by Evaluate. Complex numbers with no
+// imaginary part are printed as plain reals.
+func FormatValue(v any) string {
+	switch v := v.(type) {
+	case int:
+		return strconv.Itoa(v)
+	case complex128:
+		if imag(v) == 0 {
+			return formatReal(real(v))
+		}
+		return strconv.FormatComplex(v, 'g', -1, 128)
+	case Matrix:
+		rows := make([]string, len(v))
+		for i, row := range v {
+			cells := make([]string, len(row))
+			for j, x := range row {
+				cells[j] = formatReal(x)
+			}
+			rows[i] = "[" + strings.Join(cells, ", ") + "]"
+		}
+		return "[" + strings.Join(rows, ", ") + "]"
+	}
+	return fmt.Sprint(v)
+}
+
+func formatReal(x float64) string {
+	if x == 0 {
+		x = 0 // normalise -0
+	}
+	return strconv.FormatFloat(x, 'g', -1, 64)
+}
+
+type exprParser struct {
+	input string
+	pos   int
+}
+
+func parseExpression(expr string) (exprNode, error) {
+	p := &exprParser{input: expr}
+	if p.skipSpace(); p.done() {
+		return nil, fmt.Errorf("%w: empty expression", ErrInvalidExpression)
+	}
+
+	n, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if p.skipSpace(); !p.done() {
+		return nil, p.errorf("unexpected %q", p.input[p.pos])
+	}
+	return n, nil
+}
+
+func (p *exprParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w: %s at position %d", ErrInvalidExpression, fmt.Sprintf(format, args...), p.pos)
+}
+
+func (p *exprParser) done() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *exprParser) skipSpace() {
+	for !p.done() && (p.input[p.pos] == ' ' || p.input[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// accept consumes ch if it is the next non-space character.
+func (p *exprParser) accept(ch byte) bool {
+	p.skipSpace()
+	if !p.done() && p.input[p.pos] == ch {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *exprParser) expect(ch byte) error {
+	if !p.accept(ch) {
+		if p.done() {
+			return p.errorf("expected %q, got end of input", ch)
+		}
+		return p.errorf("expected %q, got %q", ch, p.input[p.pos])
+	}
+	return nil
+}
+
+func (p *exprParser) parseSum() (exprNode, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		var op byte
+		switch {
+		case p.accept('+'):
+			op = '+'
+		case p.accept('-'):
+			op = '-'
+		default:
+			return left, nil
+		}
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseProduct() (exprNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		var op byte
+		switch {
+		case p.accept('*'):
+			op = '*'
+		case p.accept('/'):
+			op = '/'
+		default:
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if !p.accept('-') {
+		return p.parsePower()
+	}
+
+	operand, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	// Fold negative number literals so "-3" stays a single value.
+	if lit, ok := operand.(literalNode); ok {
+		switch v := lit.value.(type) {
+		case int:
+			return literalNode{value: -v}, nil
+		case complex128:
+			return literalNode{value: -v}, nil
+		}
+	}
+	return negateNode{operand: operand}, nil
+}
+
+func (p *exprParser) parsePower() (exprNode, error) {
+	base, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if !p.accept('^') {
+		return base, nil
+	}
+	// Exponentiation is right-associative: 2^3^2 = 2^(3^2).
+	exp, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	return binaryNode{op: '^', left: base, right: exp}, nil
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	p.skipSpace()
+	if p.done() {
+		return nil, p.errorf("unexpected end of input")
+	}
+
+	ch := p.input[p.pos]
+	switch {
+	case ch == '(':
+		p.pos++
+		n, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(')'); err != nil {
+			return nil, err
+		}
+		return n, nil
+	case ch == '[':
+		return p.parseMatrix()
+	case isDigit(ch):
+		return p.parseNumber()
+	case isLetter(ch):
+		return p.parseIdentifier()
+	}
+	return nil, p.errorf("unexpected %q", ch)
+}
+
+func (p *exprParser) parseNumber() (exprNode, error) {
+	start := p.pos
+	for !p.done() && isDigit(p.input[p.pos]) {
+		p.pos++
+	}
+	isReal := false
+	if !p.done() && p.input[p.pos] == '.' {
+		isReal = true
+		p.pos++
+		for !p.done() && isDigit(p.input[p.pos]) {
+			p.pos++
+		}
+	}
+	text := p.input[start:p.pos]
+
+	if !p.done() && p.input[p.pos] == 'i' && (p.pos+1 == len(p.input) || !isLetter(p.input[p.pos+1])) {
+		p.pos++
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, p.errorf("invalid number %q", text)
+		}
+		return literalNode{value: complex(0, f)}, nil
+	}
+
+	if isReal {
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, p.errorf("invalid number %q", text)
+		}
+		return literalNode{value: complex(f, 0)}, nil
+	}
+
+	n, err := strconv.Atoi(text)
+	if err != nil {
+		return nil, p.errorf("invalid integer %q", text)
+	}
+	return literalNode{value: n}, nil
+}
+
+func (p *exprParser) parseIdentifier() (exprNode, error) {
+	start := p.pos
+	for !p.done() && isLetter(p.input[p.pos]) {
+		p.pos++
+	}
+	name := p.input[start:p.pos]
+
+	if name == "i" {
+		return literalNode{value: complex(0, 1)}, nil
+	}
+
+	if !p.accept('(') {
+		p.pos = start
+		return nil, p.errorf("unknown identifier %q", name)
+	}
+
+	var args []exprNode
+	if !p.accept(')') {
+		for {
+			arg, err := p.parseSum()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if p.accept(')') {
+				break
+			}
+			if err := p.expect(','); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return callNode{name: name, args: args}, nil
+}
+
+// parseMatrix parses [[a, b], [c, d]] or the row vector shorthand [a, b].
+// Entries must be real number literals.
+func (p *exprParser) parseMatrix() (exprNode, error) {
+	if err := p.expect('['); err != nil {
+		return nil, err
+	}
+
+	p.skipSpace()
+	if !p.done() && p.input[p.pos] != '[' {
+		row, err := p.parseRow()
+		if err != nil {
+			return nil, err
+		}
+		return literalNode{value: Matrix{row}}, nil
+	}
+
+	var m Matrix
+	for {
+		if err := p.expect('['); err != nil {
+			return nil, err
+		}
+		row, err := p.parseRow()
+		if err != nil {
+			return nil, err
+		}
+		m = append(m, row)
+		if p.accept(']') {
+			break
+		}
+		if err := p.expect(','); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, _, err := m.Dims(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidExpression, err)
+	}
+	return literalNode{value: m}, nil
+}
+
+// parseRow parses comma-separated reals up to and including the closing ']'.
+func (p *exprParser) parseRow() ([]float64, error) {
+	var row []float64
+	for {
+		p.skipSpace()
+		start := p.pos
+		if !p.done() && (p.input[p.pos] == '-' || p.input[p.pos] == '+') {
+			p.pos++
+		}
+		for !p.done() && (isDigit(p.input[p.pos]) || p.input[p.pos] == '.') {
+			p.pos++
+		}
+		f, err := strconv.ParseFloat(p.input[start:p.pos], 64)
+		if err != nil {
+			p.pos = start
+			return nil, p.errorf("invalid matrix entry")
+		}
+		row = append(row, f)
+
+		if p.accept(']') {
+			return row, nil
+		}
+		if err := p.expect(','); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func isDigit(ch byte) bool {
+	return ch >= '0' && ch <= '9'
+}
+
+func isLetter(ch byte) bool {
+	return ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z'
+}
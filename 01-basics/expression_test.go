@@ -0,0 +1,101 @@
+package basics
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	calc := Calculator{}
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"precedence", "3 + 4 * 2", "11"},
+		{"parentheses", "(3 + 4) * 2", "14"},
+		{"integer division", "7 / 2", "3"},
+		{"power is right associative", "2 ^ 3 ^ 2", "512"},
+		{"unary minus", "-(2 + 3) * 2", "-10"},
+		{"real promotes to complex", "1.5 * 2", "3"},
+		{"imaginary unit", "i * i", "-1"},
+		{"complex sum", "1 + 2i", "(1+2i)"},
+		{"complex division", "(-1 + 3i) / (1 + i)", "(1+2i)"},
+		{"conjugate", "conj(1 + 2i)", "(1-2i)"},
+		{"matrix sum", "[[1, 2], [3, 4]] + [[1, 1], [1, 1]]", "[[2, 3], [4, 5]]"},
+		{"matrix product", "[[1, 2], [3, 4]] * [[5, 6], [7, 8]]", "[[19, 22], [43, 50]]"},
+		{"scalar times matrix", "2 * [[1, -2]]", "[[2, -4]]"},
+		{"transpose", "transpose([1, 2, 3])", "[[1], [2], [3]]"},
+		{"determinant", "det([[1, 2], [3, 4]])", "-2"},
+		{"inverse", "inv([[2, 0], [0, 4]])", "[[0.5, 0], [0, 0.25]]"},
+		{"solve", "solve([[2, 1], [1, 3]], [5, 10])", "[[1, 3]]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := calc.Evaluate(tt.input)
+			if err != nil {
+				t.Fatalf("Evaluate(%q) unexpected error: %v", tt.input, err)
+			}
+			if FormatValue(got) != tt.want {
+				t.Errorf("Evaluate(%q) = %s; want %s", tt.input, FormatValue(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateLargePowers(t *testing.T) {
+	calc := Calculator{}
+	tests := []struct {
+		input string
+		want  any
+	}{
+		{"1 ^ 999999999999", 1},
+		{"(-1) ^ 999999999999", -1},
+		{"0 ^ 999999999999", 0},
+		{"2 ^ 62", 1 << 62},
+		{"(-2) ^ 63", -1 << 63},
+		{"i ^ 1000000001", complex(0, 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := calc.Evaluate(tt.input)
+			if err != nil || got != tt.want {
+				t.Errorf("Evaluate(%q) = %v, %v; want %v", tt.input, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	calc := Calculator{}
+	tests := []struct {
+		name    string
+		input   string
+		wantErr error
+	}{
+		{"empty", "", ErrInvalidExpression},
+		{"dangling operator", "1 +", ErrInvalidExpression},
+		{"unbalanced parentheses", "(1 + 2", ErrInvalidExpression},
+		{"ragged matrix", "[[1, 2], [3]]", ErrInvalidExpression},
+		{"unknown function", "sqrt(4)", ErrUnknownFunction},
+		{"division by zero", "1 / (2 - 2)", ErrDivisionByZero},
+		{"negative exponent", "2 ^ -1", ErrNegativeExponent},
+		{"huge exponent", "2^999999999999", ErrPowerOverflow},
+		{"overflowing power", "10 ^ 19", ErrPowerOverflow},
+		{"huge complex exponent", "i ^ 99999999999.0", ErrPowerOverflow},
+		{"matrix plus scalar", "[[1]] + 1", ErrUnsupportedOperand},
+		{"dimension mismatch", "[[1, 2]] * [[1, 2]]", ErrDimensionMismatch},
+		{"singular inverse", "inv([[1, 2], [2, 4]])", ErrSingularMatrix},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := calc.Evaluate(tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Evaluate(%q) error = %v; want %v", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,311 @@
+package basics
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+var ErrInvalidIDN = errors.New("invalid internationalized domain name")
+
+// acePrefix marks a label encoded with punycode.
+const acePrefix = "xn--"
+
+// DomainToASCII converts a possibly Unicode domain to its ASCII form,
+// encoding each non-ASCII label with punycode ("例子.广告" becomes
+// "xn--fsqu00a.xn--4rr70v"). The domain is first mapped as UTS #46 does,
+// so full-width and compatibility characters, decomposed accents and upper
+// case all give the same result, and ideographic full stops are treated as
+// dots. The result is checked for valid labels and lengths; code points
+// IDNA disallows, such as symbols and punctuation, are rejected.
+func DomainToASCII(domain string) (string, error) {
+	labels := splitDomain(mapDomain(domain))
+	for i, label := range labels {
+		if isASCII(label) {
+			if err := checkASCIILabel(label); err != nil {
+				return "", err
+			}
+			labels[i] = label
+			continue
+		}
+
+		if err := checkUnicodeLabel(label); err != nil {
+			return "", err
+		}
+		encoded, err := punycodeEncode(label)
+		if err != nil {
+			return "", err
+		}
+		labels[i] = acePrefix + encoded
+		if len(labels[i]) > maxLabelLength {
+			return "", fmt.Errorf("%w: label %q is too long", ErrInvalidIDN, label)
+		}
+	}
+
+	ascii := strings.Join(labels, ".")
+	if len(ascii) > maxDomainLength {
+		return "", fmt.Errorf("%w: domain is too long", ErrInvalidIDN)
+	}
+	return ascii, nil
+}
+
+// DomainToUnicode converts punycode labels back to Unicode. It accepts the
+// same input as DomainToASCII, so it can also be used to normalize a
+// Unicode domain.
+func DomainToUnicode(domain string) (string, error) {
+	ascii, err := DomainToASCII(domain)
+	if err != nil {
+		return "", err
+	}
+
+	labels := strings.Split(ascii, ".")
+	for i, label := range labels {
+		if encoded, ok := strings.CutPrefix(label, acePrefix); ok {
+			// Already validated by DomainToASCII.
+			labels[i], _ = punycodeDecode(encoded)
+		}
+	}
+	return strings.Join(labels, "."), nil
+}
+
+// mapDomain applies the UTS #46 mapping: ignored code points such as the
+// soft hyphen are removed, and the rest are lowercased and brought to NFKC.
+// Unlike case folding, lowercasing keeps "ß" and final "ς", as
+// nontransitional processing requires.
+func mapDomain(domain string) string {
+	domain = strings.Map(func(r rune) rune {
+		if isIgnoredInDomain(r) {
+			return -1
+		}
+		return r
+	}, domain)
+	return norm.NFKC.String(strings.Map(unicode.ToLower, norm.NFKC.String(domain)))
+}
+
+// isIgnoredInDomain reports whether UTS #46 maps r to nothing.
+func isIgnoredInDomain(r rune) bool {
+	switch r {
+	case 0x00AD, 0x034F, 0x200B, 0x2060, 0xFEFF:
+		return true
+	}
+	return r >= 0x180B && r <= 0x180F || unicode.Is(unicode.Variation_Selector, r)
+}
+
+// splitDomain splits on "." and the other full stops IDNA maps to it.
+func splitDomain(domain string) []string {
+	return strings.Split(fullStops.Replace(domain), ".")
+}
+
+var fullStops = strings.NewReplacer("。", ".", "．", ".", "｡", ".")
+
+func checkASCIILabel(label string) error {
+	if err := validateHostLabel(label); err != nil {
+		return err
+	}
+	encoded, ok := strings.CutPrefix(label, acePrefix)
+	if !ok {
+		// Labels with hyphens in positions 3 and 4 are reserved for
+		// encodings such as punycode.
+		if len(label) >= 4 && label[2:4] == "--" {
+			return fmt.Errorf("%w: label %q uses a reserved prefix", ErrInvalidIDN, label)
+		}
+		return nil
+	}
+
+	decoded, err := punycodeDecode(encoded)
+	if err != nil {
+		return err
+	}
+	if isASCII(decoded) || decoded != mapDomain(decoded) {
+		return fmt.Errorf("%w: %q is not a canonical punycode label", ErrInvalidIDN, label)
+	}
+	return checkUnicodeLabel(decoded)
+}
+
+// checkUnicodeLabel accepts letters, digits, combining marks and inner
+// hyphens. A label may not start with a combining mark.
+func checkUnicodeLabel(label string) error {
+	if label == "" || !utf8.ValidString(label) {
+		return fmt.Errorf("%w: empty or malformed label", ErrInvalidIDN)
+	}
+	if strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+		return fmt.Errorf("%w: label %q starts or ends with a hyphen", ErrInvalidIDN, label)
+	}
+	for i, r := range label {
+		switch {
+		case unicode.Is(unicode.M, r):
+			if i == 0 {
+				return fmt.Errorf("%w: label %q starts with a combining mark", ErrInvalidIDN, label)
+			}
+		case unicode.IsLetter(r), unicode.IsDigit(r), r == '-':
+		default:
+			return fmt.Errorf("%w: unexpected %q", ErrInvalidIDN, r)
+		}
+	}
+	return nil
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// Punycode parameters from RFC 3492 section 5.
+const (
+	punyBase        = 36
+	punyTMin        = 1
+	punyTMax        = 26
+	punySkew        = 38
+	punyDamp        = 700
+	punyInitialBias = 72
+	punyInitialN    = 128
+)
+
+func punycodeEncode(label string) (string, error) {
+	input := []rune(label)
+	var out strings.Builder
+	for _, r := range input {
+		if r < punyInitialN {
+			out.WriteRune(r)
+		}
+	}
+	basic := out.Len()
+	if basic > 0 {
+		out.WriteByte('-')
+	}
+
+	n, delta, bias := rune(punyInitialN), 0, punyInitialBias
+	for handled := basic; handled < len(input); {
+		next := rune(unicode.MaxRune + 1)
+		for _, r := range input {
+			if r >= n && r < next {
+				next = r
+			}
+		}
+		delta += int(next-n) * (handled + 1)
+		n = next
+
+		for _, r := range input {
+			if r < n {
+				delta++
+			}
+			if r != n {
+				continue
+			}
+			q := delta
+			for k := punyBase; ; k += punyBase {
+				t := punyThreshold(k, bias)
+				if q < t {
+					break
+				}
+				out.WriteByte(punyDigit(t + (q-t)%(punyBase-t)))
+				q = (q - t) / (punyBase - t)
+			}
+			out.WriteByte(punyDigit(q))
+			bias = punyAdapt(delta, handled+1, handled == basic)
+			delta = 0
+			handled++
+		}
+		delta++
+		n++
+	}
+	return out.String(), nil
+}
+
+func punycodeDecode(encoded string) (string, error) {
+	var output []rune
+	rest := encoded
+	if i := strings.LastIndexByte(encoded, '-'); i >= 0 {
+		for _, r := range encoded[:i] {
+			if r >= punyInitialN {
+				return "", fmt.Errorf("%w: bad punycode %q", ErrInvalidIDN, encoded)
+			}
+			output = append(output, r)
+		}
+		rest = encoded[i+1:]
+	}
+
+	n, i, bias := rune(punyInitialN), 0, punyInitialBias
+	for pos := 0; pos < len(rest); {
+		oldI, w := i, 1
+		for k := punyBase; ; k += punyBase {
+			if pos >= len(rest) {
+				return "", fmt.Errorf("%w: truncated punycode %q", ErrInvalidIDN, encoded)
+			}
+			digit, ok := punyValue(rest[pos])
+			pos++
+			if !ok || digit > (1<<31-1-i)/w {
+				return "", fmt.Errorf("%w: bad punycode %q", ErrInvalidIDN, encoded)
+			}
+			i += digit * w
+			t := punyThreshold(k, bias)
+			if digit < t {
+				break
+			}
+			w *= punyBase - t
+		}
+
+		length := len(output) + 1
+		bias = punyAdapt(i-oldI, length, oldI == 0)
+		n += rune(i / length)
+		i %= length
+		if n > unicode.MaxRune || (n >= 0xD800 && n <= 0xDFFF) {
+			return "", fmt.Errorf("%w: bad punycode %q", ErrInvalidIDN, encoded)
+		}
+		output = append(output[:i], append([]rune{n}, output[i:]...)...)
+		i++
+	}
+	return string(output), nil
+}
+
+func punyThreshold(k, bias int) int {
+	switch {
+	case k <= bias+punyTMin:
+		return punyTMin
+	case k >= bias+punyTMax:
+		return punyTMax
+	}
+	return k - bias
+}
+
+func punyAdapt(delta, numPoints int, first bool) int {
+	if first {
+		delta /= punyDamp
+	} else {
+		delta /= 2
+	}
+	delta += delta / numPoints
+	k := 0
+	for delta > ((punyBase-punyTMin)*punyTMax)/2 {
+		delta /= punyBase - punyTMin
+		k += punyBase
+	}
+	return k + (punyBase-punyTMin+1)*delta/(delta+punySkew)
+}
+
+func punyDigit(d int) byte {
+	if d < 26 {
+		return byte('a' + d)
+	}
+	return byte('0' + d - 26)
+}
+
+func punyValue(c byte) (int, bool) {
+	switch {
+	case c >= 'a' && c <= 'z':
+		return int(c - 'a'), true
+	case c >= 'A' && c <= 'Z':
+		return int(c - 'A'), true
+	case c >= '0' && c <= '9':
+		return int(c-'0') + 26, true
+	}
+	return 0, false
+}
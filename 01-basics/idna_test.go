@@ -0,0 +1,110 @@
+package basics
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDomainToASCII(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"example.com", "example.com"},
+		{"Example.COM", "example.com"},
+		{"bücher.de", "xn--bcher-kva.de"},
+		{"MÜNCHEN.de", "xn--mnchen-3ya.de"},
+		{"例子.广告", "xn--fsqu00a.xn--4rr70v"},
+		{"例子。广告", "xn--fsqu00a.xn--4rr70v"},
+		{"дом.рф", "xn--d1aqf.xn--p1ai"},
+		{"xn--bcher-kva.de", "xn--bcher-kva.de"},
+		{"bu\u0308cher.de", "xn--bcher-kva.de"},
+		{"BU\u0308CHER.DE", "xn--bcher-kva.de"},
+		{"ＥＸＡＭＰＬＥ.com", "example.com"},
+		{"ｅｘａｍｐｌｅ．ｃｏｍ", "example.com"},
+		{"\ufb01.com", "fi.com"},
+		{"bü\u00adcher.de", "xn--bcher-kva.de"},
+		{"faß.de", "xn--fa-hia.de"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := DomainToASCII(tt.input)
+			if err != nil {
+				t.Fatalf("DomainToASCII(%q) returned error: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("DomainToASCII(%q) = %q; want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDomainToUnicode(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"xn--bcher-kva.de", "bücher.de"},
+		{"xn--fsqu00a.xn--4rr70v", "例子.广告"},
+		{"XN--D1AQF.XN--P1AI", "дом.рф"},
+		{"example.com", "example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := DomainToUnicode(tt.input)
+			if err != nil {
+				t.Fatalf("DomainToUnicode(%q) returned error: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("DomainToUnicode(%q) = %q; want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDomainToASCIIInvalid(t *testing.T) {
+	tests := []string{
+		"",
+		"example..com",
+		"-bücher.de",
+		"bücher-.de",
+		"bü cher.de",
+		"\u0301accent.de",
+		"ab--cd.com",
+		"xn--.de",
+		"xn--bcher-kva!.de",
+		"xn--abc.de",
+		"xn--bcher-KVA.de-",
+		"xn--bcher-kva.de\u200d",
+		"☃.com",
+		"ex\u202eample.com",
+		"b\u00fc\u2044cher.de",
+		string(make([]rune, 64)) + ".com",
+	}
+
+	for _, input := range tests {
+		t.Run(input, func(t *testing.T) {
+			if _, err := DomainToASCII(input); !errors.Is(err, ErrInvalidIDN) {
+				t.Errorf("DomainToASCII(%q) error = %v; want ErrInvalidIDN", input, err)
+			}
+		})
+	}
+}
+
+func TestPunycodeRoundTrip(t *testing.T) {
+	for _, label := range []string{"bücher", "例子", "ü", "aéb", "παράδειγμα"} {
+		encoded, err := punycodeEncode(label)
+		if err != nil {
+			t.Fatalf("punycodeEncode(%q) returned error: %v", label, err)
+		}
+		decoded, err := punycodeDecode(encoded)
+		if err != nil {
+			t.Fatalf("punycodeDecode(%q) returned error: %v", encoded, err)
+		}
+		if decoded != label {
+			t.Errorf("round trip of %q gave %q via %q", label, decoded, encoded)
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package basics
+
+import (
+	"errors"
+	"math"
+)
+
+var (
+	ErrEmptyMatrix       = errors.New("matrix must have at least one row and one column")
+	ErrRaggedMatrix      = errors.New("matrix rows must all have the same length")
+	ErrDimensionMismatch = errors.New("matrix dimensions do not match")
+	ErrNotSquareMatrix   = errors.New("matrix must be square")
+	ErrSingularMatrix    = errors.New("matrix is singular")
+)
+
+// singularTolerance is the pivot magnitude below which a matrix is treated
+// as singular during elimination.
+const singularTolerance = 1e-12
+
+// Matrix is a dense row-major matrix. Every row must have the same length.
+type Matrix [][]float64
+
+// Dims returns the number of rows and columns of m, or an error if m is
+// empty or ragged.
+func (m Matrix) Dims() (rows, cols int, err error) {
+	if len(m) == 0 || len(m[0]) == 0 {
+		return 0, 0, ErrEmptyMatrix
+	}
+
+	cols = len(m[0])
+	for _, row := range m {
+		if len(row) != cols {
+			return 0, 0, ErrRaggedMatrix
+		}
+	}
+
+	return len(m), cols, nil
+}
+
+func newMatrix(rows, cols int) Matrix {
+	m := make(Matrix, rows)
+	for i := range m {
+		m[i] = make([]float64, cols)
+	}
+	return m
+}
+
+func (m Matrix) clone() Matrix {
+	out := make(Matrix, len(m))
+	for i, row := range m {
+		out[i] = append([]float64(nil), row...)
+	}
+	return out
+}
+
+func (c *Calculator) MatrixAdd(a, b Matrix) (Matrix, error) {
+	return elementwise(a, b, func(x, y float64) float64 { return x + y })
+}
+
+func (c *Calculator) MatrixSubtract(a, b Matrix) (Matrix, error) {
+	return elementwise(a, b, func(x, y float64) float64 { return x - y })
+}
+
+func elementwise(a, b Matrix, op func(x, y float64) float64) (Matrix, error) {
+	ar, ac, err := a.Dims()
+	if err != nil {
+		return nil, err
+	}
+	br, bc, err := b.Dims()
+	if err != nil {
+		return nil, err
+	}
+	if ar != br || ac != bc {
+		return nil, ErrDimensionMismatch
+	}
+
+	out := newMatrix(ar, ac)
+	for i := range out {
+		for j := range out[i] {
+			out[i][j] = op(a[i][j], b[i][j])
+		}
+	}
+	return out, nil
+}
+
+func (c *Calculator) MatrixMultiply(a, b Matrix) (Matrix, error) {
+	ar, ac, err := a.Dims()
+	if err != nil {
+		return nil, err
+	}
+	br, bc, err := b.Dims()
+	if err != nil {
+		return nil, err
+	}
+	if ac != br {
+		return nil, ErrDimensionMismatch
+	}
+
+	out := newMatrix(ar, bc)
+	for i := 0; i < ar; i++ {
+		for j := 0; j < bc; j++ {
+			var sum float64
+			for k := 0; k < ac; k++ {
+				sum += a[i][k] * b[k][j]
+			}
+			out[i][j] = sum
+		}
+	}
+	return out, nil
+}
+
+func (c *Calculator) MatrixScale(m Matrix, factor float64) (Matrix, error) {
+	rows, cols, err := m.Dims()
+	if err != nil {
+		return nil, err
+	}
+
+	out := newMatrix(rows, cols)
+	for i := range out {
+		for j := range out[i] {
+			out[i][j] = m[i][j] * factor
+		}
+	}
+	return out, nil
+}
+
+func (c *Calculator) Transpose(m Matrix) (Matrix, error) {
+	rows, cols, err := m.Dims()
+	if err != nil {
+		return nil, err
+	}
+
+	out := newMatrix(cols, rows)
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			out[j][i] = m[i][j]
+		}
+	}
+	return out, nil
+}
+
+// Determinant computes the determinant of a square matrix using Gaussian
+// elimination with partial pivoting.
+func (c *Calculator) Determinant(m Matrix) (float64, error) {
+	n, err := squareSize(m)
+	if err != nil {
+		return 0, err
+	}
+
+	a := m.clone()
+	det := 1.0
+	for col := 0; col < n; col++ {
+		pivot := pivotRow(a, col)
+		if math.Abs(a[pivot][col]) < singularTolerance {
+			return 0, nil
+		}
+		if pivot != col {
+			a[pivot], a[col] = a[col], a[pivot]
+			det = -det
+		}
+
+		det *= a[col][col]
+		for row := col + 1; row < n; row++ {
+			factor := a[row][col] / a[col][col]
+			for k := col; k < n; k++ {
+				a[row][k] -= factor * a[col][k]
+			}
+		}
+	}
+
+	return det, nil
+}
+
+// Inverse computes the inverse of a square matrix using Gauss-Jordan
+// elimination. It returns ErrSingularMatrix if no inverse exists.
+func (c *Calculator) Inverse(m Matrix) (Matrix, error) {
+	n, err := squareSize(m)
+	if err != nil {
+		return nil, err
+	}
+
+	identity := newMatrix(n, n)
+	for i := range identity {
+		identity[i][i] = 1
+	}
+
+	return gaussJordan(m.clone(), identity)
+}
+
+// Solve solves the linear system a·x = b for x. It returns
+// ErrSingularMatrix if the system has no unique solution.
+func (c *Calculator) Solve(a Matrix, b []float64) ([]float64, error) {
+	n, err := squareSize(a)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != n {
+		return nil, ErrDimensionMismatch
+	}
+
+	rhs := newMatrix(n, 1)
+	for i, v := range b {
+		rhs[i][0] = v
+	}
+
+	solved, err := gaussJordan(a.clone(), rhs)
+	if err != nil {
+		return nil, err
+	}
+
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = solved[i][0]
+	}
+	return x, nil
+}
+
+func squareSize(m Matrix) (int, error) {
+	rows, cols, err := m.Dims()
+	if err != nil {
+		return 0, err
+	}
+	if rows != cols {
+		return 0, ErrNotSquareMatrix
+	}
+	return rows, nil
+}
+
+func pivotRow(a Matrix, col int) int {
+	best := col
+	for row := col + 1; row < len(a); row++ {
+		if math.Abs(a[row][col]) > math.Abs(a[best][col]) {
+			best = row
+		}
+	}
+	return best
+}
+
+// gaussJordan reduces a to the identity while applying the same row
+// operations to rhs, which then holds a⁻¹·rhs. Both matrices are modified.
+func gaussJordan(a, rhs Matrix) (Matrix, error) {
+	n := len(a)
+	for col := 0; col < n; col++ {
+		pivot := pivotRow(a, col)
+		if math.Abs(a[pivot][col]) < singularTolerance {
+			return nil, ErrSingularMatrix
+		}
+		a[pivot], a[col] = a[col], a[pivot]
+		rhs[pivot], rhs[col] = rhs[col], rhs[pivot]
+
+		scale := a[col][col]
+		for k := range a[col] {
+			a[col][k] /= scale
+		}
+		for k := range rhs[col] {
+			rhs[col][k] /= scale
+		}
+
+		for row := 0; row < n; row++ {
+			if row == col || a[row][col] == 0 {
+				continue
+			}
+			factor := a[row][col]
+			for k := range a[row] {
+				a[row][k] -= factor * a[col][k]
+			}
+			for k := range rhs[row] {
+				rhs[row][k] -= factor * rhs[col][k]
+			}
+		}
+	}
+
+	return rhs, nil
+}
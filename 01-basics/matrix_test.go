@@ -0,0 +1,167 @@
+package basics
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func matricesEqual(a, b Matrix) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if len(a[i]) != len(b[i]) {
+			return false
+		}
+		for j := range a[i] {
+			if math.Abs(a[i][j]-b[i][j]) > 1e-9 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestMatrixAddAndMultiply(t *testing.T) {
+	calc := Calculator{}
+	a := Matrix{{1, 2}, {3, 4}}
+	b := Matrix{{5, 6}, {7, 8}}
+
+	sum, err := calc.MatrixAdd(a, b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := (Matrix{{6, 8}, {10, 12}}); !matricesEqual(sum, want) {
+		t.Errorf("MatrixAdd = %v; want %v", sum, want)
+	}
+
+	product, err := calc.MatrixMultiply(a, b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := (Matrix{{19, 22}, {43, 50}}); !matricesEqual(product, want) {
+		t.Errorf("MatrixMultiply = %v; want %v", product, want)
+	}
+}
+
+func TestTranspose(t *testing.T) {
+	calc := Calculator{}
+	got, err := calc.Transpose(Matrix{{1, 2, 3}, {4, 5, 6}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := Matrix{{1, 4}, {2, 5}, {3, 6}}
+	if !matricesEqual(got, want) {
+		t.Errorf("Transpose = %v; want %v", got, want)
+	}
+}
+
+func TestDeterminant(t *testing.T) {
+	calc := Calculator{}
+	tests := []struct {
+		name string
+		m    Matrix
+		want float64
+	}{
+		{"1x1", Matrix{{7}}, 7},
+		{"2x2", Matrix{{1, 2}, {3, 4}}, -2},
+		{"3x3", Matrix{{2, 0, 1}, {1, 3, 2}, {1, 1, 2}}, 6},
+		{"needs pivoting", Matrix{{0, 1}, {1, 0}}, -1},
+		{"singular", Matrix{{1, 2}, {2, 4}}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := calc.Determinant(tt.m)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Determinant(%v) = %g; want %g", tt.m, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInverse(t *testing.T) {
+	calc := Calculator{}
+	m := Matrix{{4, 7}, {2, 6}}
+
+	inv, err := calc.Inverse(m)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	identity, _ := calc.MatrixMultiply(m, inv)
+	if want := (Matrix{{1, 0}, {0, 1}}); !matricesEqual(identity, want) {
+		t.Errorf("m * Inverse(m) = %v; want identity", identity)
+	}
+	if m[0][0] != 4 {
+		t.Error("Inverse modified its input")
+	}
+}
+
+func TestSolve(t *testing.T) {
+	calc := Calculator{}
+	// 2x + y = 5, x + 3y = 10
+	x, err := calc.Solve(Matrix{{2, 1}, {1, 3}}, []float64{5, 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if math.Abs(x[0]-1) > 1e-9 || math.Abs(x[1]-3) > 1e-9 {
+		t.Errorf("Solve = %v; want [1 3]", x)
+	}
+}
+
+func TestMatrixErrors(t *testing.T) {
+	calc := Calculator{}
+	tests := []struct {
+		name    string
+		fn      func() error
+		wantErr error
+	}{
+		{"add mismatched", func() error {
+			_, err := calc.MatrixAdd(Matrix{{1, 2}}, Matrix{{1}, {2}})
+			return err
+		}, ErrDimensionMismatch},
+		{"multiply mismatched", func() error {
+			_, err := calc.MatrixMultiply(Matrix{{1, 2}}, Matrix{{1, 2}})
+			return err
+		}, ErrDimensionMismatch},
+		{"ragged", func() error {
+			_, err := calc.Transpose(Matrix{{1, 2}, {3}})
+			return err
+		}, ErrRaggedMatrix},
+		{"empty", func() error {
+			_, err := calc.Transpose(Matrix{})
+			return err
+		}, ErrEmptyMatrix},
+		{"determinant of non-square", func() error {
+			_, err := calc.Determinant(Matrix{{1, 2}})
+			return err
+		}, ErrNotSquareMatrix},
+		{"inverse of singular", func() error {
+			_, err := calc.Inverse(Matrix{{1, 2}, {2, 4}})
+			return err
+		}, ErrSingularMatrix},
+		{"solve singular", func() error {
+			_, err := calc.Solve(Matrix{{1, 1}, {1, 1}}, []float64{1, 2})
+			return err
+		}, ErrSingularMatrix},
+		{"solve wrong length", func() error {
+			_, err := calc.Solve(Matrix{{1, 0}, {0, 1}}, []float64{1})
+			return err
+		}, ErrDimensionMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.fn(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v; want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
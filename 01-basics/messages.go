@@ -0,0 +1,237 @@
+package basics
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ParamError attaches named parameters to an error, such as "min" for a
+// length limit, so that translated messages can mention them. errors.Is
+// sees through it to the wrapped sentinel.
+type ParamError struct {
+	Err    error
+	Params map[string]any
+	// Message is the English message. It defaults to Err.Error().
+	Message string
+}
+
+// WithParams returns err annotated with params, keeping err's message.
+func WithParams(err error, params map[string]any) *ParamError {
+	return &ParamError{Err: err, Params: params}
+}
+
+func (e *ParamError) Error() string {
+	if e.Message == "" {
+		return e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *ParamError) Unwrap() error {
+	return e.Err
+}
+
+// Translator renders a field error in the given locale.
+type Translator interface {
+	Translate(locale string, e *FieldError) string
+}
+
+// Catalog is a Translator backed by message templates keyed by locale and
+// error code. Templates refer to parameters by name, as in
+// "must be at least {min} characters".
+type Catalog struct {
+	mu       sync.RWMutex
+	fallback string
+	messages map[string]map[string]string
+}
+
+// NewCatalog returns an empty catalog. Lookups that fail in the requested
+// locale are retried in fallback.
+func NewCatalog(fallback string) *Catalog {
+	return &Catalog{fallback: normalizeLocale(fallback), messages: make(map[string]map[string]string)}
+}
+
+// Add merges messages, a map from error code to template, into locale.
+func (c *Catalog) Add(locale string, messages map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	locale = normalizeLocale(locale)
+	if c.messages[locale] == nil {
+		c.messages[locale] = make(map[string]string)
+	}
+	for code, template := range messages {
+		c.messages[locale][code] = template
+	}
+}
+
+// LoadJSON adds the messages in a JSON object such as
+//
+//	{"username.too_short": "El nombre debe tener al menos {min} caracteres"}
+func (c *Catalog) LoadJSON(locale string, r io.Reader) error {
+	var messages map[string]string
+	if err := json.NewDecoder(r).Decode(&messages); err != nil {
+		return fmt.Errorf("loading %s messages: %w", locale, err)
+	}
+	c.Add(locale, messages)
+	return nil
+}
+
+// LoadFile loads a JSON message file named after its locale, e.g.
+// "locales/pt-BR.json".
+func (c *Catalog) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return c.LoadJSON(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)), f)
+}
+
+// LoadDir loads every .json file in dir.
+func (c *Catalog) LoadDir(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		if err := c.LoadFile(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Lookup returns the template for code in locale. Region subtags fall back
+// to the base language ("pt-BR" to "pt") and then to the catalog's
+// fallback locale.
+func (c *Catalog) Lookup(locale, code string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, tag := range []string{normalizeLocale(locale), c.fallback} {
+		for {
+			if template, ok := c.messages[tag][code]; ok {
+				return template, true
+			}
+			i := strings.LastIndex(tag, "-")
+			if i < 0 {
+				break
+			}
+			tag = tag[:i]
+		}
+	}
+	return "", false
+}
+
+// Translate renders e's message in locale, or returns e.Message unchanged
+// if the catalog has no template for its code.
+func (c *Catalog) Translate(locale string, e *FieldError) string {
+	template, ok := c.Lookup(locale, e.Code)
+	if !ok {
+		return e.Message
+	}
+	return renderMessage(template, e.Params)
+}
+
+func renderMessage(template string, params map[string]any) string {
+	if len(params) == 0 {
+		return template
+	}
+	pairs := make([]string, 0, 2*len(params))
+	for name, value := range params {
+		pairs = append(pairs, "{"+name+"}", fmt.Sprint(value))
+	}
+	return strings.NewReplacer(pairs...).Replace(template)
+}
+
+func normalizeLocale(tag string) string {
+	return strings.ToLower(strings.ReplaceAll(tag, "_", "-"))
+}
+
+// Translate returns a copy of v with every message rendered in locale.
+func (v ValidationErrors) Translate(t Translator, locale string) ValidationErrors {
+	out := make(ValidationErrors, len(v))
+	for i, e := range v {
+		copied := *e
+		copied.Message = t.Translate(locale, e)
+		out[i] = &copied
+	}
+	return out
+}
+
+// DefaultCatalog holds the built-in English messages. Load further
+// languages into it, or build a separate Catalog.
+var DefaultCatalog = NewCatalog("en")
+
+func init() {
+	DefaultCatalog.Add("en", map[string]string{
+		"invalid":                         "is invalid",
+		"required":                        "is required",
+		"too_short":                       "must be at least {min} characters long",
+		"too_long":                        "must be at most {max} characters long",
+		"too_few_items":                   "must have at least {min} items",
+		"too_many_items":                  "must have at most {max} items",
+		"too_small":                       "must be at least {min}",
+		"too_large":                       "must be at most {max}",
+		"not_one_of":                      "must be one of: {allowed}",
+		"unsafe_char":                     "must not contain invisible or control characters such as {char}",
+		"username.empty":                  "Username cannot be empty",
+		"username.too_short":              "Username must be at least {min} characters long",
+		"username.too_long":               "Username must be at most {max} characters long",
+		"username.invalid_char":           "Username must not contain {char}",
+		"username.must_start_with_letter": "Username must start with a letter",
+		"username.consecutive_separators": "Username must not contain consecutive separators",
+		"username.reserved":               "Username {value} is reserved",
+		"username.mixed_script":           "Username must not mix characters from different scripts",
+		"username.confusable":             "Username looks too similar to {existing}",
+		"username.blocked":                "Username must not contain {term}",
+		"username.pattern":                "Username contains characters that are not allowed",
+		"email.invalid":                   "Email address is invalid",
+		"email.missing_at":                "Email address must contain @",
+		"email.invalid_local_part":        "The part of the email address before @ is invalid",
+		"email.invalid_domain":            "The email domain is invalid",
+		"email.too_long":                  "Email address is too long",
+		"email.display_name":              "Enter the email address without a name",
+		"email.non_ascii":                 "Email address must only contain ASCII characters",
+		"email.contains_username":         "Email address must not contain the username",
+		"email.disposable":                "Disposable email addresses from {domain} are not allowed",
+		"email.role_account":              "Use a personal email address rather than a shared one",
+		"email.undeliverable":             "The email domain {domain} does not accept mail",
+		"email.unverified":                "The email domain {domain} could not be verified, please try again",
+		"age.invalid":                     "Age must be between {min} and {max}",
+		"age.implausible":                 "Age must be at most {max}",
+		"age.under_13":                    "You must be at least 13 years old",
+		"age.under_16":                    "You must be at least 16 years old",
+		"age.under_18":                    "You must be at least 18 years old",
+		"age.under_minimum":               "You must be at least {min} years old",
+		"dob.required":                    "Date of birth is required",
+		"dob.future":                      "Date of birth cannot be in the future",
+		"username.duplicate":              "Username is already used on line {line}",
+		"email.duplicate":                 "Email address is already used on line {line}",
+		"import.invalid_value":            "This value could not be read",
+		"import.format":                   "The file could not be read",
+		"password.required":               "Password is required",
+		"password.too_short":              "Password must be at least {min} characters long",
+		"password.too_long":               "Password must be at most {max} characters long",
+		"password.missing_class":          "Password must contain a {class} character",
+		"password.common":                 "This password is too common",
+		"password.contains_username":      "Password must not contain your username",
+		"password.contains_email":         "Password must not contain your email address",
+		"password.too_weak":               "Password is too easy to guess",
+		"password.pattern":                "Password must contain these kinds of characters: {classes}",
+		"phone.required":                  "Phone number is required",
+		"phone.invalid_char":              "Phone number must not contain {char}",
+		"phone.country_required":          "Enter the phone number with its country code, starting with +",
+		"phone.invalid_country_code":      "+{code} is not a valid country code",
+		"phone.too_short":                 "Phone number is too short for {region}",
+		"phone.too_long":                  "Phone number is too long for {region}",
+		"phone.invalid":                   "This is not a valid phone number for {region}",
+		"phone.pattern":                   "Phone number may only contain digits, spaces and + ( ) - . /",
+	})
+}
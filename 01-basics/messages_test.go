@@ -0,0 +1,156 @@
+package basics
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFieldErrorParams(t *testing.T) {
+	policy := DefaultUsernamePolicy
+	policy.MinLength = 5
+
+	fe := NewFieldError("Username", "bob", policy.Validate("bob"))
+	if fe.Code != "username.too_short" || fe.Params["min"] != 5 {
+		t.Errorf("FieldError = {%s %v}; want username.too_short with min=5", fe.Code, fe.Params)
+	}
+	if !errors.Is(fe, ErrUsernameTooShort) {
+		t.Error("errors.Is(fe, ErrUsernameTooShort) = false; want true")
+	}
+}
+
+func TestCatalogLookupFallback(t *testing.T) {
+	c := NewCatalog("en")
+	c.Add("en", map[string]string{"required": "is required", "too_short": "too short"})
+	c.Add("pt", map[string]string{"required": "é obrigatório"})
+	c.Add("pt-BR", map[string]string{"too_short": "muito curto"})
+
+	tests := []struct {
+		locale string
+		code   string
+		want   string
+		ok     bool
+	}{
+		{"pt-BR", "too_short", "muito curto", true},
+		{"pt_br", "too_short", "muito curto", true},
+		{"pt-BR", "required", "é obrigatório", true},
+		{"pt-PT", "too_short", "too short", true},
+		{"de", "required", "is required", true},
+		{"de", "unknown", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale+"/"+tt.code, func(t *testing.T) {
+			got, ok := c.Lookup(tt.locale, tt.code)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("Lookup(%q, %q) = %q, %v; want %q, %v", tt.locale, tt.code, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestCatalogLoadJSON(t *testing.T) {
+	c := NewCatalog("en")
+	err := c.LoadJSON("es", strings.NewReader(`{"username.too_short": "El nombre de usuario debe tener al menos {min} caracteres"}`))
+	if err != nil {
+		t.Fatalf("LoadJSON returned error: %v", err)
+	}
+	if err := c.LoadJSON("es", strings.NewReader(`["not", "an", "object"]`)); err == nil {
+		t.Error("LoadJSON(array) returned nil error")
+	}
+
+	err = ValidateUser(User{Username: "jo", Email: "jo@example.com", Age: 20})
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("error = %v; want ValidationErrors", err)
+	}
+
+	translated := errs.Translate(c, "es-MX")
+	want := "El nombre de usuario debe tener al menos 3 caracteres"
+	if translated[0].Message != want {
+		t.Errorf("Message = %q; want %q", translated[0].Message, want)
+	}
+	if !errors.Is(translated, ErrUsernameTooShort) {
+		t.Error("translated errors no longer match ErrUsernameTooShort")
+	}
+	if errs[0].Message == want {
+		t.Error("Translate modified the original errors")
+	}
+}
+
+func TestCatalogLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"fr.json":   `{"age.invalid": "L'âge doit être compris entre {min} et {max}"}`,
+		"de.json":   `{"age.invalid": "Das Alter muss zwischen {min} und {max} liegen"}`,
+		"notes.txt": `ignored`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := NewCatalog("en")
+	if err := c.LoadDir(dir); err != nil {
+		t.Fatalf("LoadDir returned error: %v", err)
+	}
+
+	fe := NewFieldError("Age", 200, ValidateAge(200))
+	if got, want := c.Translate("fr-CA", fe), "L'âge doit être compris entre 0 et 150"; got != want {
+		t.Errorf("Translate(fr-CA) = %q; want %q", got, want)
+	}
+	if got, want := c.Translate("de", fe), "Das Alter muss zwischen 0 und 150 liegen"; got != want {
+		t.Errorf("Translate(de) = %q; want %q", got, want)
+	}
+	// No English messages were loaded, so the original message is kept.
+	if got := c.Translate("it", fe); got != fe.Message {
+		t.Errorf("Translate(it) = %q; want %q", got, fe.Message)
+	}
+
+	if err := c.LoadFile(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("LoadFile(missing) returned nil error")
+	}
+}
+
+func TestDefaultCatalogCoversErrorCodes(t *testing.T) {
+	sentinels := []error{
+		ErrEmptyUsername, ErrUsernameTooShort, ErrUsernameTooLong, ErrUsernameInvalidChar,
+		ErrUsernameMustStartWithLetter, ErrUsernameConsecutiveSeparators, ErrUsernameReserved,
+		ErrInvalidEmail, ErrEmailMissingAt, ErrEmailLocalPart, ErrEmailDomain, ErrEmailTooLong,
+		ErrEmailDisplayName, ErrEmailNonASCII, ErrEmailContainsUsername, ErrInvalidAge,
+		ErrRequired, ErrTooShort, ErrTooLong, ErrTooSmall, ErrTooLarge, ErrNotOneOf,
+		ErrTooFewItems, ErrTooManyItems,
+		ErrUsernameMixedScript, ErrUsernameConfusable, ErrUsernameBlocked,
+		ErrDateOfBirthRequired, ErrDateOfBirthInFuture, ErrImplausibleAge,
+		ErrUnderAge13, ErrUnderAge16, ErrUnderAge18, ErrUnderMinimumAge,
+		ErrEmailDisposable, ErrEmailRoleAccount, ErrEmailUndeliverable, ErrEmailDomainUnverified,
+		ErrPasswordRequired, ErrPasswordTooShort, ErrPasswordTooLong, ErrPasswordMissingClass,
+		ErrPasswordCommon, ErrPasswordContainsUsername, ErrPasswordContainsEmail, ErrPasswordTooWeak,
+		ErrDuplicateUsername, ErrDuplicateEmail, ErrImportInvalidValue, ErrImportFormat,
+		ErrUnsafeChar, ErrPhoneRequired, ErrPhoneInvalidChar, ErrPhoneCountryRequired,
+		ErrPhoneInvalidCountryCode, ErrPhoneTooShort, ErrPhoneTooLong, ErrPhoneInvalidNumber,
+	}
+	for _, err := range sentinels {
+		code := ErrorCode(err)
+		if _, ok := DefaultCatalog.Lookup("en", code); !ok {
+			t.Errorf("DefaultCatalog has no English message for %q", code)
+		}
+	}
+}
+
+type upperTranslator struct{}
+
+func (upperTranslator) Translate(_ string, e *FieldError) string {
+	return strings.ToUpper(e.Message)
+}
+
+func TestValidationErrorsTranslateCustomTranslator(t *testing.T) {
+	errs := ValidationErrors{NewFieldError("Email", "x", ErrInvalidEmail)}
+	got := errs.Translate(upperTranslator{}, "en")
+	if got[0].Message != "INVALID EMAIL FORMAT" {
+		t.Errorf("Message = %q; want %q", got[0].Message, "INVALID EMAIL FORMAT")
+	}
+}
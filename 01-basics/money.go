@@ -0,0 +1,207 @@
+package basics
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrUnknownCurrency  = errors.New("unknown currency code")
+	ErrInvalidAmount    = errors.New("invalid money amount")
+	ErrCurrencyMismatch = errors.New("currencies do not match")
+	ErrAmountOverflow   = errors.New("money amount overflows")
+)
+
+// Currency is an ISO 4217 currency. MinorUnits is the number of decimal
+// places used by the currency, e.g. 2 for USD (cents) and 0 for JPY.
+type Currency struct {
+	Code       string
+	MinorUnits int
+}
+
+// currencies is the subset of ISO 4217 supported by LookupCurrency.
+var currencies = map[string]int{
+	"AUD": 2, "BHD": 3, "BRL": 2, "CAD": 2, "CHF": 2, "CLP": 0, "CNY": 2,
+	"CZK": 2, "DKK": 2, "EUR": 2, "GBP": 2, "HKD": 2, "HUF": 2, "IDR": 2,
+	"ILS": 2, "INR": 2, "ISK": 0, "JOD": 3, "JPY": 0, "KRW": 0, "KWD": 3,
+	"MXN": 2, "NOK": 2, "NZD": 2, "OMR": 3, "PLN": 2, "SEK": 2, "SGD": 2,
+	"THB": 2, "TND": 3, "TRY": 2, "USD": 2, "VND": 0, "ZAR": 2,
+}
+
+func LookupCurrency(code string) (Currency, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	units, ok := currencies[code]
+	if !ok {
+		return Currency{}, fmt.Errorf("%w: %q", ErrUnknownCurrency, code)
+	}
+	return Currency{Code: code, MinorUnits: units}, nil
+}
+
+// Money is an amount in the minor units of a currency, so arithmetic is
+// exact integer arithmetic. The zero value has no currency and is not valid.
+type Money struct {
+	minor    int64
+	currency Currency
+}
+
+// NewMoney creates an amount from minor units: NewMoney(1234, "USD") is
+// 12.34 USD.
+func NewMoney(minor int64, code string) (Money, error) {
+	currency, err := LookupCurrency(code)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{minor: minor, currency: currency}, nil
+}
+
+// ParseMoney parses a decimal amount such as "-12.5". Amounts with more
+// decimal places than the currency allows are rejected rather than rounded.
+func ParseMoney(amount, code string) (Money, error) {
+	currency, err := LookupCurrency(code)
+	if err != nil {
+		return Money{}, err
+	}
+
+	amount = strings.TrimSpace(amount)
+	intPart, fracPart, _ := strings.Cut(amount, ".")
+	if len(fracPart) > currency.MinorUnits {
+		return Money{}, fmt.Errorf("%w: %q has more than %d decimal places for %s", ErrInvalidAmount, amount, currency.MinorUnits, currency.Code)
+	}
+	if strings.ContainsAny(fracPart, "+-") || intPart == "" || intPart == "-" || intPart == "+" {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, amount)
+	}
+
+	digits := intPart + fracPart + strings.Repeat("0", currency.MinorUnits-len(fracPart))
+	minor, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		if errors.Is(err, strconv.ErrRange) {
+			return Money{}, fmt.Errorf("%w: %q", ErrAmountOverflow, amount)
+		}
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, amount)
+	}
+	return Money{minor: minor, currency: currency}, nil
+}
+
+func (m Money) Minor() int64 {
+	return m.minor
+}
+
+func (m Money) Currency() Currency {
+	return m.currency
+}
+
+func (m Money) IsZero() bool {
+	return m.minor == 0
+}
+
+// String formats the amount with exactly the currency's minor units, for
+// example "12.30 USD" or "-5 JPY".
+func (m Money) String() string {
+	return formatMinor(m.minor, m.currency.MinorUnits) + " " + m.currency.Code
+}
+
+func formatMinor(minor int64, units int) string {
+	sign := ""
+	u := uint64(minor)
+	if minor < 0 {
+		sign = "-"
+		u = -u
+	}
+
+	digits := strconv.FormatUint(u, 10)
+	if units == 0 {
+		return sign + digits
+	}
+	if len(digits) <= units {
+		digits = strings.Repeat("0", units-len(digits)+1) + digits
+	}
+	cut := len(digits) - units
+	return sign + digits[:cut] + "." + digits[cut:]
+}
+
+func (m Money) sameCurrency(o Money) error {
+	if m.currency.Code != o.currency.Code {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.currency.Code, o.currency.Code)
+	}
+	return nil
+}
+
+// Add returns m + o. Both amounts must be in the same currency; convert
+// one of them first with a Converter otherwise.
+func (m Money) Add(o Money) (Money, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return Money{}, err
+	}
+
+	// Amounts stay in int64: Calculator works on int, which is 32 bits on
+	// some platforms.
+	sum := m.minor + o.minor
+	if (o.minor > 0 && sum < m.minor) || (o.minor < 0 && sum > m.minor) {
+		return Money{}, ErrAmountOverflow
+	}
+	return Money{minor: sum, currency: m.currency}, nil
+}
+
+func (m Money) Subtract(o Money) (Money, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return Money{}, err
+	}
+	if o.minor == math.MinInt64 {
+		return Money{}, ErrAmountOverflow
+	}
+	return m.Add(Money{minor: -o.minor, currency: o.currency})
+}
+
+// Multiply scales m by an integer factor, such as a quantity.
+func (m Money) Multiply(factor int64) (Money, error) {
+	product := m.minor * factor
+	if factor != 0 && (product/factor != m.minor || (m.minor == math.MinInt64 && factor == -1)) {
+		return Money{}, ErrAmountOverflow
+	}
+	return Money{minor: product, currency: m.currency}, nil
+}
+
+// Allocate splits m into n parts that differ by at most one minor unit and
+// sum exactly to m. Earlier parts receive the remainder.
+func (m Money) Allocate(n int) ([]Money, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("%w: cannot allocate into %d parts", ErrInvalidAmount, n)
+	}
+
+	if n == 0 {
+		return nil, ErrDivisionByZero
+	}
+
+	share, remainder := m.minor/int64(n), m.minor%int64(n)
+	parts := make([]Money, n)
+	for i := range parts {
+		parts[i] = Money{minor: share, currency: m.currency}
+		switch {
+		case remainder > 0:
+			parts[i].minor++
+			remainder--
+		case remainder < 0:
+			parts[i].minor--
+			remainder++
+		}
+	}
+	return parts, nil
+}
+
+// Compare returns -1, 0 or +1 depending on whether m is less than, equal to
+// or greater than o.
+func (m Money) Compare(o Money) (int, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.minor < o.minor:
+		return -1, nil
+	case m.minor > o.minor:
+		return 1, nil
+	}
+	return 0, nil
+}
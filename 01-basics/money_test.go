@@ -0,0 +1,152 @@
+package basics
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func mustParseMoney(t *testing.T, amount, code string) Money {
+	t.Helper()
+	m, err := ParseMoney(amount, code)
+	if err != nil {
+		t.Fatalf("ParseMoney(%q, %q): %v", amount, code, err)
+	}
+	return m
+}
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		name      string
+		amount    string
+		code      string
+		wantMinor int64
+		wantText  string
+		wantErr   error
+	}{
+		{"dollars and cents", "12.34", "usd", 1234, "12.34 USD", nil},
+		{"padded fraction", "12.3", "USD", 1230, "12.30 USD", nil},
+		{"whole amount", "7", "EUR", 700, "7.00 EUR", nil},
+		{"negative below one", "-0.05", "USD", -5, "-0.05 USD", nil},
+		{"zero minor units", "1500", "JPY", 1500, "1500 JPY", nil},
+		{"three minor units", "1.5", "KWD", 1500, "1.500 KWD", nil},
+		{"too many decimals", "1.234", "USD", 0, "", ErrInvalidAmount},
+		{"decimals for JPY", "10.5", "JPY", 0, "", ErrInvalidAmount},
+		{"not a number", "abc", "USD", 0, "", ErrInvalidAmount},
+		{"unknown currency", "1", "XYZ", 0, "", ErrUnknownCurrency},
+		{"overflow", "99999999999999999999", "USD", 0, "", ErrAmountOverflow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMoney(tt.amount, tt.code)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseMoney(%q, %q) error = %v; want %v", tt.amount, tt.code, err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got.Minor() != tt.wantMinor || got.String() != tt.wantText {
+				t.Errorf("ParseMoney(%q, %q) = %d (%s); want %d (%s)", tt.amount, tt.code, got.Minor(), got, tt.wantMinor, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	a := mustParseMoney(t, "10.10", "USD")
+	b := mustParseMoney(t, "0.20", "USD")
+
+	sum, err := a.Add(b)
+	if err != nil || sum.String() != "10.30 USD" {
+		t.Errorf("Add = %v, %v; want 10.30 USD", sum, err)
+	}
+
+	diff, err := b.Subtract(a)
+	if err != nil || diff.String() != "-9.90 USD" {
+		t.Errorf("Subtract = %v, %v; want -9.90 USD", diff, err)
+	}
+
+	product, err := b.Multiply(3)
+	if err != nil || product.String() != "0.60 USD" {
+		t.Errorf("Multiply = %v, %v; want 0.60 USD", product, err)
+	}
+
+	if cmp, err := a.Compare(b); err != nil || cmp != 1 {
+		t.Errorf("Compare = %d, %v; want 1", cmp, err)
+	}
+}
+
+func TestMoneyRejectsMixedCurrencies(t *testing.T) {
+	usd := mustParseMoney(t, "1", "USD")
+	eur := mustParseMoney(t, "1", "EUR")
+
+	if _, err := usd.Add(eur); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Add error = %v; want ErrCurrencyMismatch", err)
+	}
+	if _, err := usd.Subtract(eur); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Subtract error = %v; want ErrCurrencyMismatch", err)
+	}
+	if _, err := usd.Compare(eur); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Compare error = %v; want ErrCurrencyMismatch", err)
+	}
+}
+
+func TestMoneyOverflow(t *testing.T) {
+	big, _ := NewMoney(1<<62, "USD")
+
+	if _, err := big.Add(big); !errors.Is(err, ErrAmountOverflow) {
+		t.Errorf("Add error = %v; want ErrAmountOverflow", err)
+	}
+	if _, err := big.Multiply(4); !errors.Is(err, ErrAmountOverflow) {
+		t.Errorf("Multiply error = %v; want ErrAmountOverflow", err)
+	}
+
+	maxUSD, _ := NewMoney(math.MaxInt64, "USD")
+	minUSD, _ := NewMoney(math.MinInt64, "USD")
+	one, _ := NewMoney(1, "USD")
+	if _, err := maxUSD.Add(one); !errors.Is(err, ErrAmountOverflow) {
+		t.Errorf("max Add(1) error = %v; want ErrAmountOverflow", err)
+	}
+	if _, err := one.Subtract(minUSD); !errors.Is(err, ErrAmountOverflow) {
+		t.Errorf("Subtract(min) error = %v; want ErrAmountOverflow", err)
+	}
+	if _, err := minUSD.Multiply(-1); !errors.Is(err, ErrAmountOverflow) {
+		t.Errorf("min Multiply(-1) error = %v; want ErrAmountOverflow", err)
+	}
+
+	// Amounts beyond 32 bits must not be truncated on the way through.
+	wide, _ := NewMoney(1<<40, "USD")
+	if sum, err := wide.Add(wide); err != nil || sum.Minor() != 1<<41 {
+		t.Errorf("Add = %d, %v; want %d", sum.Minor(), err, int64(1<<41))
+	}
+	if product, err := wide.Multiply(3); err != nil || product.Minor() != 3<<40 {
+		t.Errorf("Multiply = %d, %v; want %d", product.Minor(), err, int64(3<<40))
+	}
+}
+
+func TestMoneyAllocate(t *testing.T) {
+	m := mustParseMoney(t, "100.00", "USD")
+
+	parts, err := m.Allocate(3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"33.34 USD", "33.33 USD", "33.33 USD"}
+	for i, part := range parts {
+		if part.String() != want[i] {
+			t.Errorf("part %d = %s; want %s", i, part, want[i])
+		}
+	}
+
+	wide, _ := NewMoney(1<<40+1, "USD")
+	parts, err = wide.Allocate(2)
+	if err != nil || parts[0].Minor() != 1<<39+1 || parts[1].Minor() != 1<<39 {
+		t.Errorf("Allocate(2) = %v, %v; want parts of %d and %d", parts, err, int64(1<<39+1), int64(1<<39))
+	}
+
+	if _, err := m.Allocate(0); !errors.Is(err, ErrDivisionByZero) {
+		t.Errorf("Allocate(0) error = %v; want ErrDivisionByZero", err)
+	}
+}
@@ -0,0 +1,337 @@
+package basics
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrInvalidNumberWords = errors.New("invalid number words")
+	ErrOrdinalOutOfRange  = errors.New("ordinal out of range")
+	ErrUnknownLocale      = errors.New("unknown locale")
+	ErrNumberOutOfRange   = errors.New("number out of range")
+)
+
+// NumberLocale spells numbers in a particular language and parses them
+// back. Implementations must round-trip: ParseCardinal(Cardinal(n)) == n.
+type NumberLocale interface {
+	Cardinal(n int64) string
+	Ordinal(n int64) (string, error)
+	ParseCardinal(words string) (int64, error)
+	ParseOrdinal(words string) (int64, error)
+	// DecimalPoint is the word read between the integer and fractional
+	// digits, such as "point".
+	DecimalPoint() string
+}
+
+var (
+	numberLocalesMu sync.RWMutex
+	numberLocales   = map[string]NumberLocale{
+		"en": English,
+		"es": Spanish,
+	}
+)
+
+// RegisterNumberLocale makes a locale available to LookupNumberLocale under
+// the given language tag, replacing any existing registration.
+func RegisterNumberLocale(tag string, locale NumberLocale) {
+	numberLocalesMu.Lock()
+	defer numberLocalesMu.Unlock()
+	numberLocales[strings.ToLower(tag)] = locale
+}
+
+// LookupNumberLocale returns the locale registered for tag. Region subtags
+// fall back to the base language, so "en-GB" resolves to "en".
+func LookupNumberLocale(tag string) (NumberLocale, error) {
+	numberLocalesMu.RLock()
+	defer numberLocalesMu.RUnlock()
+
+	tag = strings.ToLower(strings.ReplaceAll(tag, "_", "-"))
+	for {
+		if locale, ok := numberLocales[tag]; ok {
+			return locale, nil
+		}
+		i := strings.LastIndex(tag, "-")
+		if i < 0 {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownLocale, tag)
+		}
+		tag = tag[:i]
+	}
+}
+
+// DecimalToWords spells a decimal number, reading the fractional digits one
+// by one: 12.05 becomes "twelve point zero five" in English. NaN, the
+// infinities and values whose integer part does not fit in an int64 fail
+// with ErrNumberOutOfRange.
+func DecimalToWords(value float64, locale NumberLocale) (string, error) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return "", fmt.Errorf("%w: %g", ErrNumberOutOfRange, value)
+	}
+	text := strconv.FormatFloat(value, 'f', -1, 64)
+	intPart, fracPart, _ := strings.Cut(text, ".")
+
+	n, err := strconv.ParseInt(intPart, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("%w: %g", ErrNumberOutOfRange, value)
+	}
+	words := locale.Cardinal(n)
+	if n == 0 && strings.HasPrefix(intPart, "-") {
+		words = negativePrefix(locale) + words
+	}
+	if fracPart == "" {
+		return words, nil
+	}
+
+	digits := make([]string, len(fracPart))
+	for i, d := range fracPart {
+		digits[i] = locale.Cardinal(int64(d - '0'))
+	}
+	return words + " " + locale.DecimalPoint() + " " + strings.Join(digits, " "), nil
+}
+
+// WordsToDecimal parses the output of DecimalToWords.
+func WordsToDecimal(words string, locale NumberLocale) (float64, error) {
+	intWords, fracWords, hasPoint := strings.Cut(words, " "+locale.DecimalPoint()+" ")
+
+	n, err := locale.ParseCardinal(intWords)
+	if err != nil {
+		return 0, err
+	}
+	if !hasPoint {
+		return float64(n), nil
+	}
+
+	var b strings.Builder
+	if n == 0 && strings.HasPrefix(strings.TrimSpace(intWords), negativePrefix(locale)) {
+		b.WriteString("-")
+	}
+	b.WriteString(strconv.FormatInt(n, 10))
+	b.WriteString(".")
+	for _, word := range strings.Fields(fracWords) {
+		d, err := locale.ParseCardinal(word)
+		if err != nil || d < 0 || d > 9 {
+			return 0, fmt.Errorf("%w: %q is not a digit", ErrInvalidNumberWords, word)
+		}
+		b.WriteByte(byte('0' + d))
+	}
+	return strconv.ParseFloat(b.String(), 64)
+}
+
+// negativePrefix derives the locale's minus word, including the trailing
+// space, from how it spells -1.
+func negativePrefix(locale NumberLocale) string {
+	return strings.TrimSuffix(locale.Cardinal(-1), locale.Cardinal(1))
+}
+
+// English spells numbers in American English without "and", for example
+// "one hundred twenty-three".
+var English NumberLocale = englishLocale{}
+
+type englishLocale struct{}
+
+var (
+	englishSmall = []string{
+		"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine",
+		"ten", "eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen",
+		"seventeen", "eighteen", "nineteen",
+	}
+	englishTens   = []string{"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety"}
+	englishScales = []string{"", "thousand", "million", "billion", "trillion", "quadrillion", "quintillion"}
+
+	englishIrregularOrdinals = map[string]string{
+		"one": "first", "two": "second", "three": "third", "five": "fifth",
+		"eight": "eighth", "nine": "ninth", "twelve": "twelfth",
+	}
+)
+
+func (englishLocale) DecimalPoint() string { return "point" }
+
+func (englishLocale) Cardinal(n int64) string {
+	if n == 0 {
+		return englishSmall[0]
+	}
+
+	var prefix string
+	u := uint64(n)
+	if n < 0 {
+		prefix = "minus "
+		u = -u
+	}
+
+	var groups []string
+	for scale := 0; u > 0; scale++ {
+		if group := u % 1000; group > 0 {
+			words := englishBelowThousand(int(group))
+			if englishScales[scale] != "" {
+				words += " " + englishScales[scale]
+			}
+			groups = append([]string{words}, groups...)
+		}
+		u /= 1000
+	}
+	return prefix + strings.Join(groups, " ")
+}
+
+func englishBelowThousand(n int) string {
+	var parts []string
+	if n >= 100 {
+		parts = append(parts, englishSmall[n/100], "hundred")
+		n %= 100
+	}
+	switch {
+	case n >= 20 && n%10 != 0:
+		parts = append(parts, englishTens[n/10]+"-"+englishSmall[n%10])
+	case n >= 20:
+		parts = append(parts, englishTens[n/10])
+	case n > 0:
+		parts = append(parts, englishSmall[n])
+	}
+	return strings.Join(parts, " ")
+}
+
+func (l englishLocale) Ordinal(n int64) (string, error) {
+	if n <= 0 {
+		return "", fmt.Errorf("%w: %d", ErrOrdinalOutOfRange, n)
+	}
+
+	words := l.Cardinal(n)
+	cut := strings.LastIndexAny(words, " -") + 1
+	head, last := words[:cut], words[cut:]
+
+	if irregular, ok := englishIrregularOrdinals[last]; ok {
+		return head + irregular, nil
+	}
+	if strings.HasSuffix(last, "y") {
+		return head + strings.TrimSuffix(last, "y") + "ieth", nil
+	}
+	return head + last + "th", nil
+}
+
+func (l englishLocale) ParseOrdinal(words string) (int64, error) {
+	words = strings.ToLower(strings.TrimSpace(words))
+	cut := strings.LastIndexAny(words, " -") + 1
+	head, last := words[:cut], words[cut:]
+
+	cardinal := ""
+	for c, o := range englishIrregularOrdinals {
+		if last == o {
+			cardinal = c
+		}
+	}
+	switch {
+	case cardinal != "":
+	case strings.HasSuffix(last, "ieth"):
+		cardinal = strings.TrimSuffix(last, "ieth") + "y"
+	case strings.HasSuffix(last, "th"):
+		cardinal = strings.TrimSuffix(last, "th")
+	default:
+		return 0, fmt.Errorf("%w: %q is not an ordinal", ErrInvalidNumberWords, words)
+	}
+
+	n, err := l.ParseCardinal(head + cardinal)
+	if err != nil {
+		return 0, err
+	}
+	if n <= 0 {
+		return 0, fmt.Errorf("%w: %q", ErrOrdinalOutOfRange, words)
+	}
+	return n, nil
+}
+
+func (englishLocale) ParseCardinal(words string) (int64, error) {
+	fields := strings.Fields(strings.ToLower(strings.ReplaceAll(words, "-", " ")))
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("%w: empty input", ErrInvalidNumberWords)
+	}
+
+	negative := false
+	if fields[0] == "minus" || fields[0] == "negative" {
+		negative = true
+		fields = fields[1:]
+	}
+	if len(fields) == 1 && fields[0] == "zero" {
+		return 0, nil
+	}
+
+	var total, group uint64
+	lastScale := len(englishScales)
+	// state tracks what may follow inside a group: 0 = anything,
+	// 1 = after tens (units only), 2 = after units/teens (hundred or scale).
+	state := 0
+	for _, word := range fields {
+		if word == "and" {
+			continue
+		}
+		if n := indexOf(englishSmall[1:], word) + 1; n > 0 {
+			if state == 2 || (state == 1 && n >= 10) {
+				return 0, fmt.Errorf("%w: unexpected %q", ErrInvalidNumberWords, word)
+			}
+			group += uint64(n)
+			state = 2
+			continue
+		}
+		if n := indexOf(englishTens, word); n >= 2 {
+			if state != 0 || group%100 != 0 {
+				return 0, fmt.Errorf("%w: unexpected %q", ErrInvalidNumberWords, word)
+			}
+			group += uint64(n * 10)
+			state = 1
+			continue
+		}
+		if word == "hundred" {
+			if group == 0 || group > 9 {
+				return 0, fmt.Errorf("%w: unexpected %q", ErrInvalidNumberWords, word)
+			}
+			group *= 100
+			state = 0
+			continue
+		}
+		scale := indexOf(englishScales, word)
+		if scale < 1 || scale >= lastScale || group == 0 {
+			return 0, fmt.Errorf("%w: unexpected %q", ErrInvalidNumberWords, word)
+		}
+		if group > math.MaxUint64/pow1000(scale) {
+			return 0, fmt.Errorf("%w: %q overflows int64", ErrInvalidNumberWords, words)
+		}
+		total += group * pow1000(scale)
+		group, state, lastScale = 0, 0, scale
+	}
+
+	return signedWordsValue(total+group, negative, words)
+}
+
+func indexOf(words []string, word string) int {
+	for i, w := range words {
+		if w == word {
+			return i
+		}
+	}
+	return -1
+}
+
+func pow1000(scale int) uint64 {
+	result := uint64(1)
+	for i := 0; i < scale; i++ {
+		result *= 1000
+	}
+	return result
+}
+
+// signedWordsValue applies the sign to a parsed magnitude, rejecting values
+// outside the int64 range.
+func signedWordsValue(magnitude uint64, negative bool, words string) (int64, error) {
+	if negative {
+		if magnitude > 1<<63 {
+			return 0, fmt.Errorf("%w: %q overflows int64", ErrInvalidNumberWords, words)
+		}
+		return -int64(magnitude-1) - 1, nil
+	}
+	if magnitude > 1<<63-1 {
+		return 0, fmt.Errorf("%w: %q overflows int64", ErrInvalidNumberWords, words)
+	}
+	return int64(magnitude), nil
+}
@@ -0,0 +1,296 @@
+package basics
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Spanish spells numbers in Spanish using the long scale, so 10^9 is
+// "mil millones" and 10^12 is "un billón".
+var Spanish NumberLocale = spanishLocale{}
+
+type spanishLocale struct{}
+
+var (
+	spanishSmall = []string{
+		"cero", "uno", "dos", "tres", "cuatro", "cinco", "seis", "siete", "ocho", "nueve",
+		"diez", "once", "doce", "trece", "catorce", "quince", "dieciséis", "diecisiete",
+		"dieciocho", "diecinueve", "veinte", "veintiuno", "veintidós", "veintitrés",
+		"veinticuatro", "veinticinco", "veintiséis", "veintisiete", "veintiocho", "veintinueve",
+	}
+	spanishTens     = []string{"", "", "", "treinta", "cuarenta", "cincuenta", "sesenta", "setenta", "ochenta", "noventa"}
+	spanishHundreds = []string{
+		"", "ciento", "doscientos", "trescientos", "cuatrocientos", "quinientos",
+		"seiscientos", "setecientos", "ochocientos", "novecientos",
+	}
+	// spanishScales are the long-scale powers of a million, singular and plural.
+	spanishScales = [][2]string{{"", ""}, {"millón", "millones"}, {"billón", "billones"}, {"trillón", "trillones"}}
+
+	spanishOrdinalUnits = []string{
+		"", "primero", "segundo", "tercero", "cuarto", "quinto", "sexto", "séptimo", "octavo", "noveno",
+		"décimo", "undécimo", "duodécimo", "decimotercero", "decimocuarto", "decimoquinto",
+		"decimosexto", "decimoséptimo", "decimoctavo", "decimonoveno",
+	}
+	spanishOrdinalTens = []string{
+		"", "", "vigésimo", "trigésimo", "cuadragésimo", "quincuagésimo",
+		"sexagésimo", "septuagésimo", "octogésimo", "nonagésimo",
+	}
+	spanishOrdinalHundreds = []string{
+		"", "centésimo", "ducentésimo", "tricentésimo", "cuadringentésimo", "quingentésimo",
+		"sexcentésimo", "septingentésimo", "octingentésimo", "noningentésimo",
+	}
+)
+
+func (spanishLocale) DecimalPoint() string { return "coma" }
+
+func (spanishLocale) Cardinal(n int64) string {
+	if n == 0 {
+		return spanishSmall[0]
+	}
+
+	var prefix string
+	u := uint64(n)
+	if n < 0 {
+		prefix = "menos "
+		u = -u
+	}
+
+	var groups []string
+	for scale := 0; u > 0; scale++ {
+		group := u % 1_000_000
+		u /= 1_000_000
+		if group == 0 {
+			continue
+		}
+
+		switch {
+		case scale == 0:
+			groups = append([]string{spanishBelowMillion(int(group), false)}, groups...)
+		case group == 1:
+			groups = append([]string{"un " + spanishScales[scale][0]}, groups...)
+		default:
+			groups = append([]string{spanishBelowMillion(int(group), true) + " " + spanishScales[scale][1]}, groups...)
+		}
+	}
+	return prefix + strings.Join(groups, " ")
+}
+
+// spanishBelowMillion spells 1..999999. With apocope set, a trailing "uno"
+// is shortened as it is before a noun: "veintiún millones".
+func spanishBelowMillion(n int, apocope bool) string {
+	var parts []string
+	if thousands := n / 1000; thousands > 0 {
+		if thousands > 1 {
+			parts = append(parts, spanishBelowThousand(thousands, true))
+		}
+		parts = append(parts, "mil")
+	}
+	if rest := n % 1000; rest > 0 {
+		parts = append(parts, spanishBelowThousand(rest, apocope))
+	}
+	return strings.Join(parts, " ")
+}
+
+func spanishBelowThousand(n int, apocope bool) string {
+	if n == 100 {
+		return "cien"
+	}
+
+	var parts []string
+	if n >= 100 {
+		parts = append(parts, spanishHundreds[n/100])
+		n %= 100
+	}
+	switch {
+	case n >= 30 && n%10 != 0:
+		parts = append(parts, spanishTens[n/10], "y", spanishSmall[n%10])
+	case n >= 30:
+		parts = append(parts, spanishTens[n/10])
+	case n > 0:
+		parts = append(parts, spanishSmall[n])
+	}
+
+	if apocope && len(parts) > 0 {
+		last := parts[len(parts)-1]
+		switch last {
+		case "uno":
+			parts[len(parts)-1] = "un"
+		case "veintiuno":
+			parts[len(parts)-1] = "veintiún"
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// Ordinal supports 1..999, which covers everyday use; larger Spanish
+// ordinals are rarely spelled out.
+func (spanishLocale) Ordinal(n int64) (string, error) {
+	if n <= 0 || n > 999 {
+		return "", fmt.Errorf("%w: %d", ErrOrdinalOutOfRange, n)
+	}
+
+	var parts []string
+	if n >= 100 {
+		parts = append(parts, spanishOrdinalHundreds[n/100])
+		n %= 100
+	}
+	if n >= 20 {
+		parts = append(parts, spanishOrdinalTens[n/10])
+		n %= 10
+	}
+	if n > 0 {
+		parts = append(parts, spanishOrdinalUnits[n])
+	}
+	return strings.Join(parts, " "), nil
+}
+
+func (spanishLocale) ParseOrdinal(words string) (int64, error) {
+	fields := strings.Fields(foldSpanish(words))
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("%w: empty input", ErrInvalidNumberWords)
+	}
+
+	var total int64
+	last := int64(1000)
+	for _, word := range fields {
+		value := spanishOrdinalValue(word)
+		if value == 0 && strings.HasSuffix(word, "a") {
+			// Feminine forms: "primera", "vigésima".
+			value = spanishOrdinalValue(strings.TrimSuffix(word, "a") + "o")
+		}
+		// Hundreds may be followed by tens or units, tens only by 1..9, and
+		// 1..19 end the ordinal.
+		if value == 0 || value >= last || last < 20 || last < 100 && value >= 10 {
+			return 0, fmt.Errorf("%w: unexpected %q", ErrInvalidNumberWords, word)
+		}
+		total += value
+		last = value
+	}
+	return total, nil
+}
+
+func spanishOrdinalValue(word string) int64 {
+	for _, table := range []struct {
+		words []string
+		unit  int64
+	}{
+		{spanishOrdinalUnits, 1},
+		{spanishOrdinalTens, 10},
+		{spanishOrdinalHundreds, 100},
+	} {
+		for i, w := range table.words {
+			if w != "" && foldSpanish(w) == word {
+				return int64(i) * table.unit
+			}
+		}
+	}
+	return 0
+}
+
+func (spanishLocale) ParseCardinal(words string) (int64, error) {
+	fields := strings.Fields(foldSpanish(words))
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("%w: empty input", ErrInvalidNumberWords)
+	}
+
+	negative := false
+	if fields[0] == "menos" {
+		negative = true
+		fields = fields[1:]
+	}
+	if len(fields) == 1 && fields[0] == "cero" {
+		return 0, nil
+	}
+
+	// group collects 0..999, chunk collects 0..999999 below the next
+	// million-based scale word.
+	var total, chunk, group uint64
+	lastScale := len(spanishScales)
+	afterTens := false
+	for i, word := range fields {
+		switch {
+		case word == "y":
+			if !afterTens || i == len(fields)-1 {
+				return 0, fmt.Errorf("%w: unexpected %q", ErrInvalidNumberWords, word)
+			}
+			continue
+		case word == "mil":
+			if chunk >= 1000 {
+				return 0, fmt.Errorf("%w: unexpected %q", ErrInvalidNumberWords, word)
+			}
+			chunk += max(group, 1) * 1000
+			group = 0
+		case word == "cien":
+			if group != 0 {
+				return 0, fmt.Errorf("%w: unexpected %q", ErrInvalidNumberWords, word)
+			}
+			group = 100
+		default:
+			if value, ok := spanishWordValue(word); ok {
+				if value >= 100 && group != 0 || value < 100 && group%100 != 0 && !(afterTens && value < 10) {
+					return 0, fmt.Errorf("%w: unexpected %q", ErrInvalidNumberWords, word)
+				}
+				group += value
+				afterTens = value >= 30 && value < 100
+				continue
+			}
+
+			scale := spanishScaleIndex(word)
+			if scale < 1 || scale >= lastScale || chunk+group == 0 {
+				return 0, fmt.Errorf("%w: unexpected %q", ErrInvalidNumberWords, word)
+			}
+			multiplier := pow1000(2 * scale)
+			if chunk+group > math.MaxUint64/multiplier {
+				return 0, fmt.Errorf("%w: %q overflows int64", ErrInvalidNumberWords, words)
+			}
+			total += (chunk + group) * multiplier
+			chunk, group, lastScale = 0, 0, scale
+		}
+		afterTens = false
+	}
+
+	return signedWordsValue(total+chunk+group, negative, words)
+}
+
+func spanishWordValue(word string) (uint64, bool) {
+	switch word {
+	case "un", "una":
+		return 1, true
+	case "veintiun":
+		return 21, true
+	}
+	for i, w := range spanishSmall[1:] {
+		if foldSpanish(w) == word {
+			return uint64(i + 1), true
+		}
+	}
+	for i, w := range spanishTens {
+		if w != "" && w == word {
+			return uint64(i * 10), true
+		}
+	}
+	for i, w := range spanishHundreds {
+		if w != "" && w == word {
+			return uint64(i * 100), true
+		}
+	}
+	return 0, false
+}
+
+func spanishScaleIndex(word string) int {
+	for i, forms := range spanishScales {
+		if i > 0 && (foldSpanish(forms[0]) == word || forms[1] == word) {
+			return i
+		}
+	}
+	return -1
+}
+
+// foldSpanish lowercases and strips acute accents so that input typed
+// without them ("dieciseis") still parses.
+func foldSpanish(s string) string {
+	return spanishAccents.Replace(strings.ToLower(s))
+}
+
+var spanishAccents = strings.NewReplacer("á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u")
@@ -0,0 +1,97 @@
+package basics
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSpanishCardinal(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "cero"},
+		{16, "dieciséis"},
+		{21, "veintiuno"},
+		{31, "treinta y uno"},
+		{100, "cien"},
+		{101, "ciento uno"},
+		{555, "quinientos cincuenta y cinco"},
+		{1000, "mil"},
+		{21_000, "veintiún mil"},
+		{100_000, "cien mil"},
+		{1_000_000, "un millón"},
+		{31_000_000, "treinta y un millones"},
+		{1_000_000_000, "mil millones"},
+		{2_000_000_000_000, "dos billones"},
+		{-7, "menos siete"},
+	}
+
+	for _, tt := range tests {
+		if got := Spanish.Cardinal(tt.n); got != tt.want {
+			t.Errorf("Cardinal(%d) = %q; want %q", tt.n, got, tt.want)
+		}
+		back, err := Spanish.ParseCardinal(tt.want)
+		if err != nil || back != tt.n {
+			t.Errorf("ParseCardinal(%q) = %d, %v; want %d", tt.want, back, err, tt.n)
+		}
+	}
+}
+
+func TestSpanishParseWithoutAccents(t *testing.T) {
+	got, err := Spanish.ParseCardinal("dieciseis mil veintitres")
+	if err != nil || got != 16_023 {
+		t.Errorf("ParseCardinal = %d, %v; want 16023", got, err)
+	}
+}
+
+func TestSpanishOrdinal(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{1, "primero"},
+		{13, "decimotercero"},
+		{21, "vigésimo primero"},
+		{100, "centésimo"},
+		{123, "centésimo vigésimo tercero"},
+	}
+
+	for _, tt := range tests {
+		got, err := Spanish.Ordinal(tt.n)
+		if err != nil || got != tt.want {
+			t.Errorf("Ordinal(%d) = %q, %v; want %q", tt.n, got, err, tt.want)
+		}
+	}
+
+	if got, err := Spanish.ParseOrdinal("vigésima primera"); err != nil || got != 21 {
+		t.Errorf("ParseOrdinal(feminine) = %d, %v; want 21", got, err)
+	}
+	if _, err := Spanish.Ordinal(1000); !errors.Is(err, ErrOrdinalOutOfRange) {
+		t.Errorf("Ordinal(1000) error = %v; want ErrOrdinalOutOfRange", err)
+	}
+}
+
+func TestSpanishParseInvalid(t *testing.T) {
+	invalid := []string{"", "uno uno", "y", "treinta y", "mil mil", "millón", "tercero primero"}
+
+	for _, words := range invalid {
+		_, err := Spanish.ParseCardinal(words)
+		if words == "tercero primero" {
+			_, err = Spanish.ParseOrdinal(words)
+		}
+		if !errors.Is(err, ErrInvalidNumberWords) {
+			t.Errorf("parse %q error = %v; want ErrInvalidNumberWords", words, err)
+		}
+	}
+}
+
+func TestSpanishDecimal(t *testing.T) {
+	words, err := DecimalToWords(3.14, Spanish)
+	if err != nil || words != "tres coma uno cuatro" {
+		t.Errorf("DecimalToWords(3.14) = %q, %v", words, err)
+	}
+	if got, err := WordsToDecimal(words, Spanish); err != nil || got != 3.14 {
+		t.Errorf("WordsToDecimal(%q) = %g, %v; want 3.14", words, got, err)
+	}
+}
@@ -0,0 +1,170 @@
+package basics
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestEnglishCardinal(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "zero"},
+		{7, "seven"},
+		{13, "thirteen"},
+		{40, "forty"},
+		{42, "forty-two"},
+		{100, "one hundred"},
+		{123, "one hundred twenty-three"},
+		{1005, "one thousand five"},
+		{2_000_017, "two million seventeen"},
+		{-15, "minus fifteen"},
+		{math.MaxInt64, "nine quintillion two hundred twenty-three quadrillion three hundred seventy-two trillion thirty-six billion eight hundred fifty-four million seven hundred seventy-five thousand eight hundred seven"},
+	}
+
+	for _, tt := range tests {
+		if got := English.Cardinal(tt.n); got != tt.want {
+			t.Errorf("Cardinal(%d) = %q; want %q", tt.n, got, tt.want)
+		}
+		back, err := English.ParseCardinal(tt.want)
+		if err != nil || back != tt.n {
+			t.Errorf("ParseCardinal(%q) = %d, %v; want %d", tt.want, back, err, tt.n)
+		}
+	}
+}
+
+func TestEnglishOrdinal(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{1, "first"},
+		{2, "second"},
+		{3, "third"},
+		{12, "twelfth"},
+		{20, "twentieth"},
+		{21, "twenty-first"},
+		{100, "one hundredth"},
+		{1003, "one thousand third"},
+	}
+
+	for _, tt := range tests {
+		got, err := English.Ordinal(tt.n)
+		if err != nil || got != tt.want {
+			t.Errorf("Ordinal(%d) = %q, %v; want %q", tt.n, got, err, tt.want)
+		}
+		back, err := English.ParseOrdinal(tt.want)
+		if err != nil || back != tt.n {
+			t.Errorf("ParseOrdinal(%q) = %d, %v; want %d", tt.want, back, err, tt.n)
+		}
+	}
+
+	if _, err := English.Ordinal(0); !errors.Is(err, ErrOrdinalOutOfRange) {
+		t.Errorf("Ordinal(0) error = %v; want ErrOrdinalOutOfRange", err)
+	}
+}
+
+func TestEnglishParseCardinalInvalid(t *testing.T) {
+	invalid := []string{
+		"",
+		"one one",
+		"twenty thirty",
+		"thousand",
+		"one thousand one million",
+		"banana",
+		"twenty hundred",
+		"ten quintillion",
+	}
+
+	for _, words := range invalid {
+		if _, err := English.ParseCardinal(words); !errors.Is(err, ErrInvalidNumberWords) {
+			t.Errorf("ParseCardinal(%q) error = %v; want ErrInvalidNumberWords", words, err)
+		}
+	}
+}
+
+func TestDecimalWords(t *testing.T) {
+	tests := []struct {
+		value float64
+		want  string
+	}{
+		{12.05, "twelve point zero five"},
+		{0.5, "zero point five"},
+		{-0.25, "minus zero point two five"},
+		{-3.1, "minus three point one"},
+		{42, "forty-two"},
+	}
+
+	for _, tt := range tests {
+		if got, err := DecimalToWords(tt.value, English); err != nil || got != tt.want {
+			t.Errorf("DecimalToWords(%g) = %q, %v; want %q", tt.value, got, err, tt.want)
+		}
+		back, err := WordsToDecimal(tt.want, English)
+		if err != nil || back != tt.value {
+			t.Errorf("WordsToDecimal(%q) = %g, %v; want %g", tt.want, back, err, tt.value)
+		}
+	}
+}
+
+func TestDecimalWordsOutOfRange(t *testing.T) {
+	for _, value := range []float64{1e20, -1e19, math.MaxFloat64, math.NaN(), math.Inf(1), math.Inf(-1)} {
+		if got, err := DecimalToWords(value, English); !errors.Is(err, ErrNumberOutOfRange) {
+			t.Errorf("DecimalToWords(%g) = %q, %v; want ErrNumberOutOfRange", value, got, err)
+		}
+	}
+	// The largest float64 below 2^63 still fits.
+	if _, err := DecimalToWords(math.Nextafter(1<<63, 0), English); err != nil {
+		t.Errorf("DecimalToWords(2^63 - 1024) error = %v; want nil", err)
+	}
+}
+
+func TestLookupNumberLocale(t *testing.T) {
+	if locale, err := LookupNumberLocale("en-GB"); err != nil || locale != English {
+		t.Errorf("LookupNumberLocale(en-GB) = %v, %v; want English", locale, err)
+	}
+	if locale, err := LookupNumberLocale("es_MX"); err != nil || locale != Spanish {
+		t.Errorf("LookupNumberLocale(es_MX) = %v, %v; want Spanish", locale, err)
+	}
+	if _, err := LookupNumberLocale("xx"); !errors.Is(err, ErrUnknownLocale) {
+		t.Errorf("LookupNumberLocale(xx) error = %v; want ErrUnknownLocale", err)
+	}
+
+	RegisterNumberLocale("en-Test", English)
+	if _, err := LookupNumberLocale("en-test"); err != nil {
+		t.Errorf("registered locale not found: %v", err)
+	}
+}
+
+func TestNumberWordsRoundTripCalculator(t *testing.T) {
+	calc := Calculator{}
+	results := []int{
+		calc.Add(999, 1),
+		calc.Substract(15, 2021),
+		calc.Multiply(1234, 5678),
+		calc.Power(10, 9),
+		calc.Power(21, 5),
+	}
+	quotient, _ := calc.Divide(1_000_001, 7)
+	results = append(results, quotient)
+
+	for _, locale := range []NumberLocale{English, Spanish} {
+		for _, n := range results {
+			words := locale.Cardinal(int64(n))
+			got, err := locale.ParseCardinal(words)
+			if err != nil || got != int64(n) {
+				t.Errorf("%T round trip of %d via %q = %d, %v", locale, n, words, got, err)
+			}
+		}
+		for n := int64(1); n < 1000; n++ {
+			words, err := locale.Ordinal(n)
+			if err != nil {
+				t.Fatalf("%T Ordinal(%d): %v", locale, n, err)
+			}
+			if got, err := locale.ParseOrdinal(words); err != nil || got != n {
+				t.Errorf("%T ordinal round trip of %d via %q = %d, %v", locale, n, words, got, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package basics
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	ErrPasswordRequired         = errors.New("password is required")
+	ErrPasswordTooShort         = errors.New("password is too short")
+	ErrPasswordTooLong          = errors.New("password is too long")
+	ErrPasswordMissingClass     = errors.New("password is missing a required kind of character")
+	ErrPasswordCommon           = errors.New("password is too common")
+	ErrPasswordContainsUsername = errors.New("password must not contain the username")
+	ErrPasswordContainsEmail    = errors.New("password must not contain the email address")
+	ErrPasswordTooWeak          = errors.New("password is too easy to guess")
+)
+
+//go:embed data/common_passwords.txt
+var defaultCommonPasswords string
+
+// PasswordList is a ranked list of known passwords, most common first.
+// Lookups ignore case.
+type PasswordList struct {
+	mu    sync.RWMutex
+	ranks map[string]int
+}
+
+func NewPasswordList(passwords ...string) *PasswordList {
+	l := &PasswordList{ranks: make(map[string]int)}
+	l.Add(passwords...)
+	return l
+}
+
+// DefaultCommonPasswords holds the built-in list of common passwords.
+var DefaultCommonPasswords = func() *PasswordList {
+	l := NewPasswordList()
+	// The embedded file is known to be readable.
+	_ = l.Load(strings.NewReader(defaultCommonPasswords))
+	return l
+}()
+
+// Add appends passwords after those already listed. Passwords already
+// present keep their rank.
+func (l *PasswordList) Add(passwords ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, p := range passwords {
+		key := strings.ToLower(p)
+		if _, ok := l.ranks[key]; !ok && key != "" {
+			l.ranks[key] = len(l.ranks) + 1
+		}
+	}
+}
+
+// Load reads passwords one per line, most common first. Blank lines and
+// lines starting with "#" are ignored.
+func (l *PasswordList) Load(r io.Reader) error {
+	passwords, err := readTerms(r)
+	if err != nil {
+		return err
+	}
+	l.Add(passwords...)
+	return nil
+}
+
+// Rank returns the 1-based position of password in the list.
+func (l *PasswordList) Rank(password string) (int, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	rank, ok := l.ranks[strings.ToLower(password)]
+	return rank, ok
+}
+
+func (l *PasswordList) Contains(password string) bool {
+	_, ok := l.Rank(password)
+	return ok
+}
+
+// PasswordPolicy describes an acceptable password. Lengths are counted in
+// characters (runes), not bytes.
+type PasswordPolicy struct {
+	MinLength int
+	// MaxLength bounds the work done hashing a password. Zero means no
+	// limit.
+	MaxLength     int
+	RequireLower  bool
+	RequireUpper  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	// Common, if set, rejects listed passwords and is the dictionary used
+	// for strength estimation.
+	Common *PasswordList
+	// MinScore is the lowest acceptable PasswordStrength.Score, from 0 to 4.
+	// Zero disables the check.
+	MinScore int
+	// Clock supplies the current year when guessing dates. Nil means
+	// SystemClock.
+	Clock Clock
+}
+
+// DefaultPasswordPolicy follows current guidance: it asks for length and
+// unpredictability rather than particular kinds of characters.
+var DefaultPasswordPolicy = PasswordPolicy{
+	MinLength: 8,
+	MaxLength: 128,
+	Common:    DefaultCommonPasswords,
+	MinScore:  2,
+}
+
+// Validate checks a password on its own. Prefer ValidateFor when the
+// user's username and email address are known.
+func (p PasswordPolicy) Validate(password string) error {
+	return p.ValidateFor(password, "", "")
+}
+
+// ValidateFor checks password against the policy and rejects passwords
+// containing the username or the email address's local part. Checks run
+// from the cheapest to the most expensive, and the first failure is
+// returned.
+func (p PasswordPolicy) ValidateFor(password, username, email string) error {
+	if password == "" {
+		return ErrPasswordRequired
+	}
+
+	length := utf8.RuneCountInString(password)
+	if length < p.MinLength {
+		return &ParamError{
+			Err:     ErrPasswordTooShort,
+			Params:  map[string]any{"min": p.MinLength},
+			Message: fmt.Sprintf("%v (minimum %d characters)", ErrPasswordTooShort, p.MinLength),
+		}
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		return &ParamError{
+			Err:     ErrPasswordTooLong,
+			Params:  map[string]any{"max": p.MaxLength},
+			Message: fmt.Sprintf("%v (maximum %d characters)", ErrPasswordTooLong, p.MaxLength),
+		}
+	}
+
+	if class := p.missingClass(password); class != "" {
+		return &ParamError{
+			Err:     ErrPasswordMissingClass,
+			Params:  map[string]any{"class": class},
+			Message: fmt.Sprintf("%v: %s", ErrPasswordMissingClass, class),
+		}
+	}
+
+	if p.Common != nil && p.Common.Contains(password) {
+		return ErrPasswordCommon
+	}
+
+	folded := NormalizeUsername(password)
+	if name := NormalizeUsername(username); utf8.RuneCountInString(name) >= 3 && strings.Contains(folded, name) {
+		return ErrPasswordContainsUsername
+	}
+	if local := emailLocalForPassword(email); utf8.RuneCountInString(local) >= 3 && strings.Contains(folded, local) {
+		return ErrPasswordContainsEmail
+	}
+
+	if p.MinScore > 0 {
+		estimator := PasswordEstimator{Dictionary: p.Common, Clock: p.Clock}
+		if s := estimator.Estimate(password, username, email); s.Score < p.MinScore {
+			return &ParamError{
+				Err:     ErrPasswordTooWeak,
+				Params:  map[string]any{"score": s.Score, "min": p.MinScore},
+				Message: fmt.Sprintf("%v (score %d, minimum %d)", ErrPasswordTooWeak, s.Score, p.MinScore),
+			}
+		}
+	}
+	return nil
+}
+
+// missingClass names the first required kind of character that password
+// lacks, or returns "".
+func (p PasswordPolicy) missingClass(password string) string {
+	var lower, upper, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || r == ' ':
+			symbol = true
+		}
+	}
+	switch {
+	case p.RequireLower && !lower:
+		return "lowercase"
+	case p.RequireUpper && !upper:
+		return "uppercase"
+	case p.RequireDigit && !digit:
+		return "digit"
+	case p.RequireSymbol && !symbol:
+		return "symbol"
+	}
+	return ""
+}
+
+// emailLocalForPassword returns the normalized local part of email without
+// any "+tag".
+func emailLocalForPassword(email string) string {
+	local, _, _ := strings.Cut(strings.TrimSpace(email), "@")
+	local, _, _ = strings.Cut(local, "+")
+	return NormalizeUsername(local)
+}
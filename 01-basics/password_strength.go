@@ -0,0 +1,617 @@
+package basics
+
+import (
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// PasswordPattern is a guessable part of a password.
+type PasswordPattern struct {
+	// Kind is one of "dictionary", "user_input", "keyboard", "sequence",
+	// "repeat", "date" or "bruteforce".
+	Kind  string
+	Token string
+	// Start and End are rune offsets into the password.
+	Start, End int
+	// Guesses estimates how many attempts an attacker needs for this part.
+	Guesses float64
+
+	// warning explains the weakness to the user.
+	warning string
+}
+
+// PasswordStrength is the result of estimating how hard a password is to
+// guess.
+type PasswordStrength struct {
+	// Score ranges from 0 (guessable within a thousand attempts) to 4
+	// (more than ten billion attempts).
+	Score   int
+	Guesses float64
+	// Entropy is log2(Guesses), in bits.
+	Entropy float64
+	// Patterns is the cheapest way found to guess the password, in order.
+	Patterns []PasswordPattern
+	// Feedback lists what makes the password weak and how to improve it.
+	// It is empty for strong passwords.
+	Feedback []string
+}
+
+// PasswordEstimator estimates password strength by finding the cheapest
+// combination of known patterns that produces the password, in the manner
+// of zxcvbn. Characters outside any pattern are costed as brute force.
+type PasswordEstimator struct {
+	// Dictionary ranks common passwords. Nil means DefaultCommonPasswords.
+	Dictionary *PasswordList
+	// Clock supplies the current year, since recent years are guessed
+	// first. Nil means SystemClock.
+	Clock Clock
+}
+
+// EstimatePasswordStrength estimates password with the default estimator.
+// userInputs such as the username and email address are treated as words an
+// attacker would try first.
+func EstimatePasswordStrength(password string, userInputs ...string) PasswordStrength {
+	return PasswordEstimator{}.Estimate(password, userInputs...)
+}
+
+// Score thresholds, in guesses.
+var passwordScoreLimits = [...]float64{1e3, 1e6, 1e8, 1e10}
+
+// Estimate scores password. See EstimatePasswordStrength.
+func (e PasswordEstimator) Estimate(password string, userInputs ...string) PasswordStrength {
+	runes := []rune(password)
+	if len(runes) == 0 {
+		return PasswordStrength{Guesses: 1, Feedback: []string{"Use a few words, avoid common phrases"}}
+	}
+
+	patterns := e.bestPatterns(runes, userInputs)
+	bits := 0.0
+	for _, p := range patterns {
+		bits += math.Log2(p.Guesses)
+	}
+	guesses := math.Exp2(bits)
+
+	s := PasswordStrength{Guesses: guesses, Entropy: bits, Patterns: patterns}
+	for s.Score < len(passwordScoreLimits) && guesses >= passwordScoreLimits[s.Score] {
+		s.Score++
+	}
+	s.Feedback = passwordFeedback(s)
+	return s
+}
+
+// bestPatterns finds the sequence of patterns covering runes with the
+// fewest total guesses, by dynamic programming over prefix lengths.
+func (e PasswordEstimator) bestPatterns(runes []rune, userInputs []string) []PasswordPattern {
+	matches := e.matches(runes, userInputs)
+	charBits := math.Log2(bruteforceCardinality(runes))
+
+	n := len(runes)
+	best := make([]float64, n+1)
+	// from[k] is the match ending at k on the best path, or -1 for a
+	// brute-forced character.
+	from := make([]int, n+1)
+	for k := 1; k <= n; k++ {
+		best[k], from[k] = best[k-1]+charBits, -1
+		for i, m := range matches {
+			if m.End == k {
+				if bits := best[m.Start] + math.Log2(m.Guesses); bits < best[k] {
+					best[k], from[k] = bits, i
+				}
+			}
+		}
+	}
+
+	var out []PasswordPattern
+	for k := n; k > 0; {
+		if i := from[k]; i >= 0 {
+			out = append(out, matches[i])
+			k = matches[i].Start
+			continue
+		}
+		start := k - 1
+		for start > 0 && from[start] == -1 {
+			start--
+		}
+		token := runes[start:k]
+		out = append(out, PasswordPattern{
+			Kind:    "bruteforce",
+			Token:   string(token),
+			Start:   start,
+			End:     k,
+			Guesses: math.Exp2(charBits * float64(len(token))),
+		})
+		k = start
+	}
+	slices.Reverse(out)
+	return out
+}
+
+// bruteforceCardinality is the size of the smallest character set that
+// contains all of runes, built from the usual classes.
+func bruteforceCardinality(runes []rune) float64 {
+	var lower, upper, digit, symbol, other bool
+	for _, r := range runes {
+		switch {
+		case r >= 'a' && r <= 'z':
+			lower = true
+		case r >= 'A' && r <= 'Z':
+			upper = true
+		case r >= '0' && r <= '9':
+			digit = true
+		case r < unicode.MaxASCII:
+			symbol = true
+		default:
+			other = true
+		}
+	}
+	n := 0.0
+	for _, c := range []struct {
+		present bool
+		size    float64
+	}{{lower, 26}, {upper, 26}, {digit, 10}, {symbol, 33}, {other, 100}} {
+		if c.present {
+			n += c.size
+		}
+	}
+	return n
+}
+
+func (e PasswordEstimator) matches(runes []rune, userInputs []string) []PasswordPattern {
+	var out []PasswordPattern
+	out = append(out, e.dictionaryMatches(runes, userInputs)...)
+	out = append(out, keyboardMatches(runes)...)
+	out = append(out, sequenceMatches(runes)...)
+	out = append(out, e.repeatMatches(runes, userInputs)...)
+	out = append(out, e.dateMatches(runes)...)
+	for i := range out {
+		out[i].Guesses = max(out[i].Guesses, minPatternGuesses)
+	}
+	return out
+}
+
+// minPatternGuesses stops a password built from several very common parts
+// from looking easier to guess than any one of them.
+const minPatternGuesses = 10
+
+// passwordLeet undoes common character substitutions for dictionary
+// lookups.
+var passwordLeet = map[rune]rune{
+	'@': 'a', '4': 'a', '3': 'e', '1': 'i', '!': 'i', '0': 'o', '$': 's', '5': 's', '7': 't',
+}
+
+const maxDictionaryWord = 32
+
+func (e PasswordEstimator) dictionaryMatches(runes []rune, userInputs []string) []PasswordPattern {
+	dict := e.Dictionary
+	if dict == nil {
+		dict = DefaultCommonPasswords
+	}
+	inputs := userInputWords(userInputs)
+
+	var out []PasswordPattern
+	for i := range runes {
+		for j := i + 3; j <= len(runes) && j-i <= maxDictionaryWord; j++ {
+			token := runes[i:j]
+			word := strings.ToLower(string(token))
+			caseGuesses := uppercaseVariations(token)
+
+			if rank, ok := inputs[word]; ok {
+				out = append(out, PasswordPattern{
+					Kind: "user_input", Token: string(token), Start: i, End: j,
+					Guesses: float64(rank) * caseGuesses,
+					warning: "Avoid using your username or email address in your password",
+				})
+			}
+			if rank, ok := dict.Rank(word); ok {
+				warning := "This is similar to a commonly used password"
+				if i == 0 && j == len(runes) {
+					warning = "This is a commonly used password"
+					if rank <= 10 {
+						warning = "This is a top-10 common password"
+					}
+				}
+				out = append(out, PasswordPattern{
+					Kind: "dictionary", Token: string(token), Start: i, End: j,
+					Guesses: float64(rank) * caseGuesses,
+					warning: warning,
+				})
+			}
+			if unleet := unleetWord(word); unleet != word {
+				if rank, ok := dict.Rank(unleet); ok {
+					out = append(out, PasswordPattern{
+						Kind: "dictionary", Token: string(token), Start: i, End: j,
+						Guesses: float64(rank) * caseGuesses * 2,
+						warning: "Predictable substitutions like '@' instead of 'a' don't help very much",
+					})
+				}
+			}
+		}
+	}
+	return out
+}
+
+// userInputWords ranks the words an attacker would derive from what they
+// know about the user: each input whole, an email address's local part, and
+// the parts of either split on punctuation.
+func userInputWords(userInputs []string) map[string]int {
+	words := make(map[string]int)
+	add := func(w string) {
+		w = strings.ToLower(strings.TrimSpace(w))
+		if _, ok := words[w]; !ok && len([]rune(w)) >= 3 {
+			words[w] = len(words) + 1
+		}
+	}
+	for _, input := range userInputs {
+		add(input)
+		local, _, _ := strings.Cut(input, "@")
+		add(local)
+		for _, part := range strings.FieldsFunc(local, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+			add(part)
+		}
+	}
+	return words
+}
+
+func unleetWord(word string) string {
+	// Digit-only tokens are not substitutions.
+	if strings.IndexFunc(word, unicode.IsLetter) < 0 {
+		return word
+	}
+	return strings.Map(func(r rune) rune {
+		if mapped, ok := passwordLeet[r]; ok {
+			return mapped
+		}
+		return r
+	}, word)
+}
+
+// uppercaseVariations estimates the extra guesses needed for the
+// capitalization of token: none for all lowercase, a factor of two for a
+// capitalized or all-uppercase word, and more for arbitrary mixes.
+func uppercaseVariations(token []rune) float64 {
+	var upper, lower int
+	for _, r := range token {
+		switch {
+		case unicode.IsUpper(r):
+			upper++
+		case unicode.IsLower(r):
+			lower++
+		}
+	}
+	switch {
+	case upper == 0:
+		return 1
+	case lower == 0, upper == 1 && unicode.IsUpper(token[0]), upper == 1 && unicode.IsUpper(token[len(token)-1]):
+		return 2
+	}
+	variations := 0.0
+	for k := 1; k <= min(upper, lower); k++ {
+		variations += binomial(upper+lower, k)
+	}
+	return variations
+}
+
+func binomial(n, k int) float64 {
+	r := 1.0
+	for i := 1; i <= k; i++ {
+		r = r * float64(n-k+i) / float64(i)
+	}
+	return r
+}
+
+// qwertyRows lays out a US keyboard, unshifted and shifted. Each row is
+// offset by half a key from the one above, so the key at column c touches
+// columns c and c+1 of the row above.
+var qwertyRows = [...][2]string{
+	{"1234567890-=", "!@#$%^&*()_+"},
+	{"qwertyuiop[]", "QWERTYUIOP{}"},
+	{"asdfghjkl;'", "ASDFGHJKL:\""},
+	{"zxcvbnm,./", "ZXCVBNM<>?"},
+}
+
+type keyPosition struct {
+	row, col int
+	shifted  bool
+}
+
+var qwertyPositions = func() map[rune]keyPosition {
+	m := make(map[rune]keyPosition)
+	for row, keys := range qwertyRows {
+		for shift, chars := range keys {
+			for col, r := range []rune(chars) {
+				m[r] = keyPosition{row, col, shift == 1}
+			}
+		}
+	}
+	return m
+}()
+
+const (
+	keyboardStartingKeys  = 47
+	keyboardAverageDegree = 4
+)
+
+// keyboardDirection returns the direction from key a to an adjacent key b,
+// or 0 if they are not adjacent.
+func keyboardDirection(a, b keyPosition) int {
+	switch dr, dc := b.row-a.row, b.col-a.col; {
+	case dr == 0 && dc == 1:
+		return 1
+	case dr == 0 && dc == -1:
+		return 2
+	case dr == -1 && dc == 0:
+		return 3
+	case dr == -1 && dc == 1:
+		return 4
+	case dr == 1 && dc == 0:
+		return 5
+	case dr == 1 && dc == -1:
+		return 6
+	}
+	return 0
+}
+
+// keyboardMatches finds walks of four or more adjacent keys, such as
+// "qwerty" or "1qaz".
+func keyboardMatches(runes []rune) []PasswordPattern {
+	var out []PasswordPattern
+	for i := 0; i < len(runes); {
+		j, turns, shifted := i+1, 0, false
+		lastDir := 0
+		if pos, ok := qwertyPositions[runes[i]]; ok {
+			shifted = pos.shifted
+			for ; j < len(runes); j++ {
+				prev := qwertyPositions[runes[j-1]]
+				next, ok := qwertyPositions[runes[j]]
+				if !ok {
+					break
+				}
+				dir := keyboardDirection(prev, next)
+				if dir == 0 {
+					break
+				}
+				if lastDir != 0 && dir != lastDir {
+					turns++
+				}
+				lastDir = dir
+				shifted = shifted || next.shifted
+			}
+		}
+		if j-i >= 4 {
+			guesses := float64(keyboardStartingKeys*(j-i)) * math.Pow(keyboardAverageDegree, float64(turns+1))
+			if shifted {
+				guesses *= 2
+			}
+			warning := "Straight rows of keys are easy to guess"
+			if turns > 0 {
+				warning = "Short keyboard patterns are easy to guess"
+			}
+			out = append(out, PasswordPattern{
+				Kind: "keyboard", Token: string(runes[i:j]), Start: i, End: j,
+				Guesses: guesses, warning: warning,
+			})
+			i = j
+			continue
+		}
+		i++
+	}
+	return out
+}
+
+// sequenceMatches finds runs of three or more letters or digits in
+// alphabetical order, forwards or backwards, such as "abc" or "9876".
+func sequenceMatches(runes []rune) []PasswordPattern {
+	class := func(r rune) int {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return 1
+		case r >= 'A' && r <= 'Z':
+			return 2
+		case r >= '0' && r <= '9':
+			return 3
+		}
+		return 0
+	}
+
+	var out []PasswordPattern
+	for i := 0; i < len(runes)-1; {
+		delta := runes[i+1] - runes[i]
+		j := i + 1
+		if c := class(runes[i]); c != 0 && (delta == 1 || delta == -1) {
+			for j < len(runes) && class(runes[j]) == c && runes[j]-runes[j-1] == delta {
+				j++
+			}
+		}
+		if j-i >= 3 {
+			base := 26.0
+			switch first := unicode.ToLower(runes[i]); {
+			case strings.ContainsRune("az019", first):
+				base = 4
+			case class(runes[i]) == 3:
+				base = 10
+			}
+			guesses := base * float64(j-i)
+			if delta < 0 {
+				guesses *= 2
+			}
+			out = append(out, PasswordPattern{
+				Kind: "sequence", Token: string(runes[i:j]), Start: i, End: j,
+				Guesses: guesses, warning: "Sequences like abc or 6543 are easy to guess",
+			})
+			i = j - 1
+			continue
+		}
+		i++
+	}
+	return out
+}
+
+// repeatMatches finds text repeated two or more times, such as "aaa" or
+// "abcabc". A repeat costs the guesses for one copy times the number of
+// copies.
+func (e PasswordEstimator) repeatMatches(runes []rune, userInputs []string) []PasswordPattern {
+	var out []PasswordPattern
+	for i := 0; i < len(runes); {
+		bestEnd, bestBase := i, 0
+		for size := 1; i+2*size <= len(runes); size++ {
+			end := i + size
+			for end+size <= len(runes) && slices.Equal(runes[end:end+size], runes[i:i+size]) {
+				end += size
+			}
+			if end-i >= 3 && end-i > bestEnd-i && end > i+size {
+				bestEnd, bestBase = end, size
+			}
+		}
+		if bestBase == 0 {
+			i++
+			continue
+		}
+		base := runes[i : i+bestBase]
+		copies := float64((bestEnd - i) / bestBase)
+		warning := `Repeats like "aaa" are easy to guess`
+		if bestBase > 1 {
+			warning = `Repeats like "abcabcabc" are only slightly harder to guess than "abc"`
+		}
+		out = append(out, PasswordPattern{
+			Kind: "repeat", Token: string(runes[i:bestEnd]), Start: i, End: bestEnd,
+			Guesses: e.Estimate(string(base), userInputs...).Guesses * copies,
+			warning: warning,
+		})
+		i = bestEnd
+	}
+	return out
+}
+
+const minYearSpace = 20
+
+var dateWithSeparators = regexp.MustCompile(`^(\d{1,4})([\s/\\_.-])(\d{1,2})([\s/\\_.-])(\d{1,4})$`)
+
+// dateMatches finds years from 1900 to 2099 and dates written with or
+// without separators, in day-month-year, month-day-year or year-month-day
+// order.
+func (e PasswordEstimator) dateMatches(runes []rune) []PasswordPattern {
+	clock := e.Clock
+	if clock == nil {
+		clock = SystemClock
+	}
+	now := clock.Now().Year()
+	yearSpace := func(year int) float64 {
+		return float64(max(abs(year-now), minYearSpace))
+	}
+
+	var out []PasswordPattern
+	for i := range runes {
+		for j := i + 4; j <= len(runes) && j-i <= 10; j++ {
+			token := string(runes[i:j])
+			if j-i == 4 {
+				if year, err := strconv.Atoi(token); err == nil && year >= 1900 && year <= 2099 {
+					out = append(out, PasswordPattern{
+						Kind: "date", Token: token, Start: i, End: j,
+						Guesses: yearSpace(year), warning: "Recent years are easy to guess",
+					})
+				}
+			}
+
+			var year int
+			var ok bool
+			separated := false
+			if m := dateWithSeparators.FindStringSubmatch(token); m != nil && m[2] == m[4] {
+				year, ok = dateYear(m[1], m[3], m[5])
+				separated = true
+			} else if j-i <= 8 && isDigits(token) {
+				year, ok = splitDigitDate(token)
+			}
+			if ok {
+				guesses := 365 * yearSpace(year)
+				if separated {
+					guesses *= 4
+				}
+				out = append(out, PasswordPattern{
+					Kind: "date", Token: token, Start: i, End: j,
+					Guesses: guesses, warning: "Dates are often easy to guess",
+				})
+			}
+		}
+	}
+	return out
+}
+
+func isDigits(s string) bool {
+	return s != "" && strings.Trim(s, "0123456789") == ""
+}
+
+// splitDigitDate tries every way of splitting digits into a date.
+func splitDigitDate(digits string) (int, bool) {
+	for a := 1; a <= 4 && a < len(digits)-1; a++ {
+		for b := a + 1; b <= a+2 && b < len(digits); b++ {
+			if year, ok := dateYear(digits[:a], digits[a:b], digits[b:]); ok {
+				return year, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// dateYear interprets three numbers as a date with the year first or last,
+// returning the year.
+func dateYear(first, middle, last string) (int, bool) {
+	a, _ := strconv.Atoi(first)
+	b, _ := strconv.Atoi(middle)
+	c, _ := strconv.Atoi(last)
+	validDayMonth := func(x, y int) bool {
+		return (x >= 1 && x <= 31 && y >= 1 && y <= 12) || (y >= 1 && y <= 31 && x >= 1 && x <= 12)
+	}
+	if year, ok := expandYear(last); ok && validDayMonth(a, b) && len(first) <= 2 {
+		return year, true
+	}
+	if year, ok := expandYear(first); ok && b >= 1 && b <= 12 && c >= 1 && c <= 31 && len(last) <= 2 {
+		return year, true
+	}
+	return 0, false
+}
+
+// expandYear accepts four-digit years from 1900 to 2099 and two-digit years,
+// which are placed between 1950 and 2049.
+func expandYear(s string) (int, bool) {
+	year, err := strconv.Atoi(s)
+	switch {
+	case err != nil:
+		return 0, false
+	case len(s) == 4 && year >= 1900 && year <= 2099:
+		return year, true
+	case len(s) == 2 && year >= 50:
+		return 1900 + year, true
+	case len(s) == 2:
+		return 2000 + year, true
+	}
+	return 0, false
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// passwordFeedback explains a weak password's patterns, most useful
+// first.
+func passwordFeedback(s PasswordStrength) []string {
+	if s.Score >= 3 {
+		return nil
+	}
+	var out []string
+	for _, p := range s.Patterns {
+		if p.warning != "" && !slices.Contains(out, p.warning) {
+			out = append(out, p.warning)
+		}
+	}
+	if len(s.Patterns) == 1 && s.Patterns[0].Kind == "dictionary" && uppercaseVariations([]rune(s.Patterns[0].Token)) > 1 {
+		out = append(out, "Capitalization doesn't help very much")
+	}
+	return append(out, "Add another word or two. Uncommon words are better.")
+}
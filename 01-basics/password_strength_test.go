@@ -0,0 +1,93 @@
+package basics
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestEstimatePasswordStrength(t *testing.T) {
+	estimator := PasswordEstimator{Clock: fixedClock("2024-05-10")}
+
+	tests := []struct {
+		password  string
+		wantScore int
+		wantKinds []string
+	}{
+		{"password", 0, []string{"dictionary"}},
+		{"p@ssw0rd", 0, []string{"dictionary"}},
+		{"qwertyuiop", 0, []string{"dictionary"}},
+		{"zxcvbn", 0, []string{"dictionary"}},
+		{"hjkl;'", 1, []string{"keyboard"}},
+		{"abcdefgh", 0, []string{"sequence"}},
+		{"98765432", 0, []string{"sequence"}},
+		{"aaaaaaaa", 0, []string{"repeat"}},
+		{"xyzxyzxyz", 0, []string{"repeat"}},
+		{"2023", 0, []string{"date"}},
+		{"31/12/1999", 1, []string{"date"}},
+		{"johndoe2024", 0, []string{"user_input", "date"}},
+		{"xK9#mQ2$vL7!", 4, []string{"bruteforce"}},
+		{"violet tractor sings", 4, []string{"bruteforce"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.password, func(t *testing.T) {
+			s := estimator.Estimate(tt.password, "johndoe", "john@example.com")
+			if s.Score != tt.wantScore {
+				t.Errorf("Score = %d; want %d (patterns %+v)", s.Score, tt.wantScore, s.Patterns)
+			}
+			var kinds []string
+			for _, p := range s.Patterns {
+				kinds = append(kinds, p.Kind)
+			}
+			if !slices.Equal(kinds, tt.wantKinds) {
+				t.Errorf("pattern kinds = %v; want %v", kinds, tt.wantKinds)
+			}
+		})
+	}
+}
+
+func TestPasswordStrengthFeedback(t *testing.T) {
+	tests := []struct {
+		password string
+		want     string
+	}{
+		{"zaqwsxcde", "Short keyboard patterns are easy to guess"},
+		{"abcdefgh", "Sequences like abc or 6543 are easy to guess"},
+		{"zzzzzzzz", `Repeats like "aaa" are easy to guess`},
+		{"25.12.1985", "Dates are often easy to guess"},
+		{"P@ssw0rd", "Predictable substitutions like '@' instead of 'a' don't help very much"},
+		{"Password", "Capitalization doesn't help very much"},
+		{"johndoe", "Avoid using your username or email address in your password"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.password, func(t *testing.T) {
+			s := EstimatePasswordStrength(tt.password, "johndoe")
+			if !slices.Contains(s.Feedback, tt.want) {
+				t.Errorf("Feedback = %q; want it to contain %q", s.Feedback, tt.want)
+			}
+		})
+	}
+
+	if s := EstimatePasswordStrength("violet tractor sings"); len(s.Feedback) != 0 {
+		t.Errorf("Feedback for a strong password = %q; want none", s.Feedback)
+	}
+}
+
+func TestPasswordStrengthPatternsCoverPassword(t *testing.T) {
+	for _, password := range []string{"Tr0ub4dor&3", "1qaz2wsx3edc", "mañana1234!", "abcabc1990"} {
+		s := EstimatePasswordStrength(password)
+		var got string
+		end := 0
+		for _, p := range s.Patterns {
+			if p.Start != end {
+				t.Errorf("%q: pattern %q starts at %d; want %d", password, p.Token, p.Start, end)
+			}
+			got += p.Token
+			end = p.End
+		}
+		if got != password {
+			t.Errorf("%q: patterns join to %q", password, got)
+		}
+	}
+}
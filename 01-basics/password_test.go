@@ -0,0 +1,137 @@
+package basics
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPasswordPolicyValidate(t *testing.T) {
+	strict := PasswordPolicy{
+		MinLength:     10,
+		MaxLength:     20,
+		RequireLower:  true,
+		RequireUpper:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+	}
+
+	tests := []struct {
+		name     string
+		policy   PasswordPolicy
+		password string
+		wantErr  error
+	}{
+		{"default strong", DefaultPasswordPolicy, "violet tractor sings", nil},
+		{"empty", DefaultPasswordPolicy, "", ErrPasswordRequired},
+		{"too short", DefaultPasswordPolicy, "xK9#mQ2", ErrPasswordTooShort},
+		{"too long", DefaultPasswordPolicy, strings.Repeat("ab", 65), ErrPasswordTooLong},
+		{"common", DefaultPasswordPolicy, "Password123", ErrPasswordCommon},
+		{"keyboard walk", DefaultPasswordPolicy, "1qaz2wsx3edc", ErrPasswordTooWeak},
+		{"date", DefaultPasswordPolicy, "1990-01-01", ErrPasswordTooWeak},
+		{"strict ok", strict, "Violet-Tractor-7", nil},
+		{"missing upper", strict, "violet-tractor-7", ErrPasswordMissingClass},
+		{"missing digit", strict, "Violet-Tractor-x", ErrPasswordMissingClass},
+		{"missing symbol", strict, "VioletTractor7x", ErrPasswordMissingClass},
+		{"no common list", PasswordPolicy{MinLength: 8}, "password", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate(tt.password)
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("Validate(%q) error = %v; want nil", tt.password, err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate(%q) error = %v; want %v", tt.password, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPasswordPolicyValidateFor(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		wantErr  error
+	}{
+		{"unrelated", "violet tractor sings", nil},
+		{"username", "violet-JohnDoe-tractor", ErrPasswordContainsUsername},
+		{"email local part", "violet-jdoe.work-tractor", ErrPasswordContainsEmail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := DefaultPasswordPolicy.ValidateFor(tt.password, "johndoe", "jdoe.work+news@example.com")
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("ValidateFor(%q) error = %v; want nil", tt.password, err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateFor(%q) error = %v; want %v", tt.password, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPasswordPolicyParams(t *testing.T) {
+	fe := NewFieldError("Password", nil, PasswordPolicy{RequireDigit: true}.Validate("nodigits"))
+	if fe.Code != "password.missing_class" || fe.Params["class"] != "digit" {
+		t.Errorf("FieldError = {%s %v}; want password.missing_class with class=digit", fe.Code, fe.Params)
+	}
+	if got := DefaultCatalog.Translate("en", fe); got != "Password must contain a digit character" {
+		t.Errorf("Translate = %q", got)
+	}
+}
+
+func TestPasswordList(t *testing.T) {
+	l := NewPasswordList("hunter2", "letmein")
+	l.Add("HUNTER2", "swordfish")
+	if err := l.Load(strings.NewReader("# comment\n\nletmein\ncorrecthorse\n")); err != nil {
+		t.Fatalf("Load error = %v", err)
+	}
+
+	tests := []struct {
+		password string
+		rank     int
+		ok       bool
+	}{
+		{"hunter2", 1, true},
+		{"LetMeIn", 2, true},
+		{"swordfish", 3, true},
+		{"correcthorse", 4, true},
+		{"unlisted", 0, false},
+	}
+	for _, tt := range tests {
+		if rank, ok := l.Rank(tt.password); rank != tt.rank || ok != tt.ok {
+			t.Errorf("Rank(%q) = %d, %v; want %d, %v", tt.password, rank, ok, tt.rank, tt.ok)
+		}
+	}
+
+	if !DefaultCommonPasswords.Contains("QWERTY") {
+		t.Error("DefaultCommonPasswords does not contain qwerty")
+	}
+}
+
+func TestValidateUserPassword(t *testing.T) {
+	user := User{Username: "johndoe", Email: "john@example.com", Age: 30, Password: "violet tractor sings"}
+	if err := ValidateUser(user); err != nil {
+		t.Errorf("ValidateUser error = %v; want nil", err)
+	}
+
+	user.Password = "johndoe-rocks-2024"
+	err := ValidateUser(user)
+	if !errors.Is(err, ErrPasswordContainsUsername) {
+		t.Fatalf("ValidateUser error = %v; want ErrPasswordContainsUsername", err)
+	}
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || len(errs.ForField("Password")) != 1 {
+		t.Fatalf("ValidateUser error = %v; want one Password error", err)
+	}
+	if v := errs.ForField("Password")[0].Value; v != nil {
+		t.Errorf("Password error Value = %v; want nil so the password is not exposed", v)
+	}
+
+	if err := ValidatePassword("password"); !errors.Is(err, ErrPasswordCommon) {
+		t.Errorf("ValidatePassword error = %v; want ErrPasswordCommon", err)
+	}
+}
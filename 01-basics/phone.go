@@ -0,0 +1,512 @@
+package basics
+
+import (
+	"bufio"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	ErrPhoneRequired           = errors.New("phone number is required")
+	ErrPhoneInvalidChar        = errors.New("phone number contains characters that are not allowed")
+	ErrPhoneCountryRequired    = errors.New("phone number needs a country code")
+	ErrPhoneInvalidCountryCode = errors.New("phone number has an invalid country code")
+	ErrPhoneTooShort           = errors.New("phone number is too short")
+	ErrPhoneTooLong            = errors.New("phone number is too long")
+	ErrPhoneInvalidNumber      = errors.New("phone number is not valid in its country")
+)
+
+// maxE164Digits is the longest number E.164 allows, country code included.
+const maxE164Digits = 15
+
+//go:embed data/phone_metadata.txt
+var defaultPhoneMetadata string
+
+// PhoneRegion describes the numbering plan of one region, identified by
+// its ISO 3166-1 alpha-2 code.
+type PhoneRegion struct {
+	Region      string
+	CallingCode int
+	// NationalPrefix is dialled before national numbers, such as "0" in
+	// "020 7946 0958". It is not part of the E.164 number.
+	NationalPrefix string
+	// Lengths lists the allowed lengths of the national significant
+	// number, in increasing order. It must not be empty.
+	Lengths []int
+	// Pattern matches every valid national significant number.
+	Pattern *regexp.Regexp
+	// Formats are display templates, tried in order.
+	Formats []PhoneFormat
+	// LeadingDigits tells regions sharing a calling code apart. The main
+	// region of a calling code has none and takes the numbers no other
+	// region claims.
+	LeadingDigits []string
+}
+
+// PhoneFormat is a display template for some of a region's numbers. In
+// Template, X stands for the next digit and N for the national prefix,
+// which international formats leave out.
+type PhoneFormat struct {
+	Length   int
+	Prefix   string
+	Template string
+}
+
+func (f PhoneFormat) matches(nsn string) bool {
+	return len(nsn) == f.Length && strings.HasPrefix(nsn, f.Prefix) && strings.Count(f.Template, "X") == f.Length
+}
+
+func (r *PhoneRegion) format(nsn string) (PhoneFormat, bool) {
+	for _, f := range r.Formats {
+		if f.matches(nsn) {
+			return f, true
+		}
+	}
+	return PhoneFormat{}, false
+}
+
+func (r *PhoneRegion) claims(nsn string) bool {
+	for _, prefix := range r.LeadingDigits {
+		if strings.HasPrefix(nsn, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// PhoneRegions is a set of numbering plans, looked up by region or by
+// calling code.
+type PhoneRegions struct {
+	mu      sync.RWMutex
+	regions map[string]*PhoneRegion
+	codes   map[int][]*PhoneRegion
+}
+
+func NewPhoneRegions() *PhoneRegions {
+	return &PhoneRegions{regions: make(map[string]*PhoneRegion), codes: make(map[int][]*PhoneRegion)}
+}
+
+// DefaultPhoneRegions holds the embedded metadata for the most common
+// regions. Add or Load others as needed.
+var DefaultPhoneRegions = func() *PhoneRegions {
+	p := NewPhoneRegions()
+	if err := p.Load(strings.NewReader(defaultPhoneMetadata)); err != nil {
+		panic(err)
+	}
+	return p
+}()
+
+// Add adds regions, replacing existing ones with the same region code.
+// Among regions sharing a calling code, the first one added without
+// LeadingDigits is the main region. If a region lacks a valid code,
+// calling code, lengths or pattern, none are added.
+func (p *PhoneRegions) Add(regions ...*PhoneRegion) error {
+	for _, r := range regions {
+		if err := r.validate(); err != nil {
+			return err
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, r := range regions {
+		r.Region = strings.ToUpper(r.Region)
+		if old, ok := p.regions[r.Region]; ok {
+			p.codes[old.CallingCode] = slices.DeleteFunc(p.codes[old.CallingCode], func(x *PhoneRegion) bool { return x == old })
+		}
+		p.regions[r.Region] = r
+		p.codes[r.CallingCode] = append(p.codes[r.CallingCode], r)
+	}
+	return nil
+}
+
+// validate checks the fields that parsing and formatting rely on.
+func (r *PhoneRegion) validate() error {
+	switch {
+	case len(r.Region) != 2:
+		return fmt.Errorf("bad phone region %q", r.Region)
+	case r.CallingCode < 1 || r.CallingCode > 999:
+		return fmt.Errorf("phone region %s: bad calling code %d", r.Region, r.CallingCode)
+	case len(r.Lengths) == 0 || r.Lengths[0] < 1 || !slices.IsSorted(r.Lengths):
+		return fmt.Errorf("phone region %s: lengths must be positive and in increasing order", r.Region)
+	case r.Pattern == nil:
+		return fmt.Errorf("phone region %s: no pattern", r.Region)
+	}
+	return nil
+}
+
+// Load adds the regions described in r, in the format of the embedded
+// data/phone_metadata.txt file.
+func (p *PhoneRegions) Load(r io.Reader) error {
+	var regions []*PhoneRegion
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text, _, _ := strings.Cut(scanner.Text(), "#")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		region, err := parsePhoneRegion(text)
+		if err != nil {
+			return fmt.Errorf("phone metadata line %d: %w", line, err)
+		}
+		regions = append(regions, region)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return p.Add(regions...)
+}
+
+func parsePhoneRegion(text string) (*PhoneRegion, error) {
+	fields := strings.Split(text, ";")
+	if len(fields) < 5 {
+		return nil, errors.New("expected region ; calling code ; national prefix ; lengths ; pattern")
+	}
+	for len(fields) < 7 {
+		fields = append(fields, "")
+	}
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+	}
+
+	r := &PhoneRegion{Region: fields[0], NationalPrefix: fields[2]}
+	if len(r.Region) != 2 {
+		return nil, fmt.Errorf("bad region %q", fields[0])
+	}
+	code, err := strconv.Atoi(fields[1])
+	if err != nil || code < 1 || code > 999 {
+		return nil, fmt.Errorf("bad calling code %q", fields[1])
+	}
+	r.CallingCode = code
+
+	for _, part := range strings.Split(fields[3], ",") {
+		lo, hi, isRange := strings.Cut(strings.TrimSpace(part), "-")
+		first, err := strconv.Atoi(lo)
+		last := first
+		if err == nil && isRange {
+			last, err = strconv.Atoi(hi)
+		}
+		if err != nil || first < 1 || last < first || last+len(fields[1]) > maxE164Digits {
+			return nil, fmt.Errorf("bad lengths %q", fields[3])
+		}
+		for n := first; n <= last; n++ {
+			r.Lengths = append(r.Lengths, n)
+		}
+	}
+	slices.Sort(r.Lengths)
+	r.Lengths = slices.Compact(r.Lengths)
+
+	if r.Pattern, err = regexp.Compile(`^(?:` + fields[4] + `)$`); err != nil {
+		return nil, fmt.Errorf("bad pattern: %w", err)
+	}
+
+	if fields[5] != "" {
+		for _, spec := range strings.Split(fields[5], ",") {
+			key, template, ok := strings.Cut(spec, ":")
+			length, prefix, _ := strings.Cut(strings.TrimSpace(key), "/")
+			n, err := strconv.Atoi(length)
+			if !ok || err != nil || strings.Count(template, "X") != n {
+				return nil, fmt.Errorf("bad format %q", spec)
+			}
+			r.Formats = append(r.Formats, PhoneFormat{Length: n, Prefix: prefix, Template: template})
+		}
+	}
+	if fields[6] != "" {
+		for _, prefix := range strings.Split(fields[6], ",") {
+			r.LeadingDigits = append(r.LeadingDigits, strings.TrimSpace(prefix))
+		}
+	}
+	return r, nil
+}
+
+// Region returns the numbering plan of region, such as "GB".
+func (p *PhoneRegions) Region(region string) (*PhoneRegion, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	r, ok := p.regions[strings.ToUpper(region)]
+	return r, ok
+}
+
+// forNumber returns the region of a calling code that nsn belongs to.
+func (p *PhoneRegions) forNumber(code int, nsn string) (*PhoneRegion, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	var main *PhoneRegion
+	for _, r := range p.codes[code] {
+		if r.claims(nsn) {
+			return r, true
+		}
+		if main == nil && len(r.LeadingDigits) == 0 {
+			main = r
+		}
+	}
+	return main, main != nil
+}
+
+// PhoneNumber is a parsed phone number.
+type PhoneNumber struct {
+	CountryCode int
+	// NationalNumber is the national significant number: the digits after
+	// the country code, without any national prefix.
+	NationalNumber string
+	// Region is the ISO 3166-1 alpha-2 code of the region the number
+	// belongs to. It may be empty for numbers built by hand.
+	Region string
+
+	region *PhoneRegion
+}
+
+// E164 returns the number in E.164 form, such as "+442079460958". Use it
+// to store and compare numbers.
+func (n PhoneNumber) E164() string {
+	return "+" + strconv.Itoa(n.CountryCode) + n.NationalNumber
+}
+
+func (n PhoneNumber) String() string {
+	return n.E164()
+}
+
+// FormatInternational formats the number for display to anyone, such as
+// "+44 20 7946 0958".
+func (n PhoneNumber) FormatInternational() string {
+	prefix := "+" + strconv.Itoa(n.CountryCode) + " "
+	f, ok := n.displayFormat()
+	if !ok {
+		return prefix + n.NationalNumber
+	}
+	return prefix + strings.TrimSpace(applyPhoneTemplate(strings.ReplaceAll(f.Template, "N", ""), n.NationalNumber))
+}
+
+// FormatNational formats the number as dialled within its region, such as
+// "020 7946 0958".
+func (n PhoneNumber) FormatNational() string {
+	r := n.phoneRegion()
+	f, ok := n.displayFormat()
+	if !ok {
+		if r != nil {
+			return r.NationalPrefix + n.NationalNumber
+		}
+		return n.NationalNumber
+	}
+	return applyPhoneTemplate(strings.ReplaceAll(f.Template, "N", r.NationalPrefix), n.NationalNumber)
+}
+
+// FormatRFC3966 returns the number as a tel URI, such as
+// "tel:+44-20-7946-0958", for use in links.
+func (n PhoneNumber) FormatRFC3966() string {
+	international := n.FormatInternational()
+	return "tel:" + strings.Join(strings.FieldsFunc(international, func(r rune) bool {
+		return !('0' <= r && r <= '9') && r != '+'
+	}), "-")
+}
+
+func (n PhoneNumber) phoneRegion() *PhoneRegion {
+	if n.region != nil {
+		return n.region
+	}
+	if n.Region != "" {
+		if r, ok := DefaultPhoneRegions.Region(n.Region); ok && r.CallingCode == n.CountryCode {
+			return r
+		}
+	}
+	r, _ := DefaultPhoneRegions.forNumber(n.CountryCode, n.NationalNumber)
+	return r
+}
+
+func (n PhoneNumber) displayFormat() (PhoneFormat, bool) {
+	if r := n.phoneRegion(); r != nil {
+		return r.format(n.NationalNumber)
+	}
+	return PhoneFormat{}, false
+}
+
+func applyPhoneTemplate(template, digits string) string {
+	var b strings.Builder
+	for _, r := range template {
+		if r == 'X' {
+			b.WriteByte(digits[0])
+			digits = digits[1:]
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// PhonePolicy parses and validates phone numbers.
+type PhonePolicy struct {
+	// DefaultRegion is assumed for numbers written without a country code,
+	// such as "020 7946 0958" in "GB". Without it, such numbers fail with
+	// ErrPhoneCountryRequired.
+	DefaultRegion string
+	// Regions holds the numbering plans. Nil means DefaultPhoneRegions.
+	Regions *PhoneRegions
+}
+
+// DefaultPhonePolicy is the policy used by ValidatePhone. It requires
+// numbers to be written with their country code.
+var DefaultPhonePolicy = PhonePolicy{}
+
+// phoneSeparators may appear between the digits of a phone number.
+const phoneSeparators = " -./()"
+
+// Parse reads a number in international form, starting with "+" or the
+// international call prefix "00" ("011" in North America), or in the
+// national form of the default region, with or without its national
+// prefix. Spaces, dashes, dots, slashes and parentheses are ignored.
+func (p PhonePolicy) Parse(phone string) (PhoneNumber, error) {
+	regions := p.Regions
+	if regions == nil {
+		regions = DefaultPhoneRegions
+	}
+
+	phone = strings.TrimSpace(norm.NFKC.String(phone))
+	if phone == "" {
+		return PhoneNumber{}, ErrPhoneRequired
+	}
+	international := strings.HasPrefix(phone, "+")
+	var digits strings.Builder
+	for _, r := range strings.TrimPrefix(phone, "+") {
+		switch {
+		case '0' <= r && r <= '9':
+			digits.WriteRune(r)
+		case strings.ContainsRune(phoneSeparators, r):
+		default:
+			return PhoneNumber{}, &ParamError{
+				Err:     ErrPhoneInvalidChar,
+				Params:  map[string]any{"char": string(r)},
+				Message: fmt.Sprintf("%v: %q", ErrPhoneInvalidChar, r),
+			}
+		}
+	}
+	number := digits.String()
+
+	var home *PhoneRegion
+	if p.DefaultRegion != "" {
+		r, ok := regions.Region(p.DefaultRegion)
+		if !ok {
+			return PhoneNumber{}, fmt.Errorf("%w: unknown region %q", ErrPhoneInvalidCountryCode, p.DefaultRegion)
+		}
+		home = r
+	}
+	if !international {
+		switch {
+		case strings.HasPrefix(number, "00") && (home == nil || home.CallingCode != 1):
+			number, international = number[2:], true
+		case strings.HasPrefix(number, "011") && home != nil && home.CallingCode == 1:
+			number, international = number[3:], true
+		}
+	}
+
+	var code int
+	var nsn string
+	if international {
+		if number == "" {
+			// Nothing follows "+" or "00", not even a country code.
+			return PhoneNumber{}, ErrPhoneCountryRequired
+		}
+		for n := 1; n <= 3 && n <= len(number); n++ {
+			c, _ := strconv.Atoi(number[:n])
+			if _, ok := regions.forNumber(c, number[n:]); ok {
+				code, nsn = c, number[n:]
+				break
+			}
+		}
+		if code == 0 {
+			c := number[:min(3, len(number))]
+			return PhoneNumber{}, &ParamError{
+				Err:     ErrPhoneInvalidCountryCode,
+				Params:  map[string]any{"code": c},
+				Message: fmt.Sprintf("%v: +%s", ErrPhoneInvalidCountryCode, c),
+			}
+		}
+	} else {
+		if home == nil {
+			return PhoneNumber{}, ErrPhoneCountryRequired
+		}
+		code, nsn = home.CallingCode, number
+	}
+
+	region := home
+	if r, ok := regions.forNumber(code, nsn); ok {
+		region = r
+	}
+	// National numbers usually carry the national prefix; international
+	// ones sometimes do too, as in "+44 (0)20 7946 0958".
+	if prefix := region.NationalPrefix; prefix != "" && strings.HasPrefix(nsn, prefix) {
+		stripped := nsn[len(prefix):]
+		if len(stripped) >= region.Lengths[0] && (!international || region.check(nsn) != nil) {
+			nsn = stripped
+			if r, ok := regions.forNumber(code, nsn); ok {
+				region = r
+			}
+		}
+	}
+
+	if err := region.check(nsn); err != nil {
+		return PhoneNumber{}, err
+	}
+	return PhoneNumber{CountryCode: code, NationalNumber: nsn, Region: region.Region, region: region}, nil
+}
+
+// check reports whether nsn is a valid national significant number.
+func (r *PhoneRegion) check(nsn string) error {
+	shortest, longest := r.Lengths[0], r.Lengths[len(r.Lengths)-1]
+	switch {
+	case len(nsn) < shortest:
+		return &ParamError{
+			Err:     ErrPhoneTooShort,
+			Params:  map[string]any{"min": shortest, "region": r.Region},
+			Message: fmt.Sprintf("%v (minimum %d digits after the country code in %s)", ErrPhoneTooShort, shortest, r.Region),
+		}
+	case len(nsn) > longest:
+		return &ParamError{
+			Err:     ErrPhoneTooLong,
+			Params:  map[string]any{"max": longest, "region": r.Region},
+			Message: fmt.Sprintf("%v (maximum %d digits after the country code in %s)", ErrPhoneTooLong, longest, r.Region),
+		}
+	case !slices.Contains(r.Lengths, len(nsn)) || !r.Pattern.MatchString(nsn):
+		return &ParamError{
+			Err:     ErrPhoneInvalidNumber,
+			Params:  map[string]any{"region": r.Region},
+			Message: fmt.Sprintf("%v (%s)", ErrPhoneInvalidNumber, r.Region),
+		}
+	}
+	return nil
+}
+
+// Validate parses phone and discards the result.
+func (p PhonePolicy) Validate(phone string) error {
+	_, err := p.Parse(phone)
+	return err
+}
+
+// Normalize returns phone in E.164 form.
+func (p PhonePolicy) Normalize(phone string) (string, error) {
+	n, err := p.Parse(phone)
+	if err != nil {
+		return "", err
+	}
+	return n.E164(), nil
+}
+
+// ParsePhone parses phone, assuming defaultRegion for numbers without a
+// country code. defaultRegion may be empty.
+func ParsePhone(phone, defaultRegion string) (PhoneNumber, error) {
+	return PhonePolicy{DefaultRegion: defaultRegion}.Parse(phone)
+}
+
+// NormalizePhone returns phone in E.164 form, assuming defaultRegion for
+// numbers without a country code.
+func NormalizePhone(phone, defaultRegion string) (string, error) {
+	return PhonePolicy{DefaultRegion: defaultRegion}.Normalize(phone)
+}
@@ -0,0 +1,273 @@
+package basics
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestParsePhone(t *testing.T) {
+	tests := []struct {
+		input      string
+		region     string
+		wantE164   string
+		wantRegion string
+		wantErr    error
+	}{
+		{"+44 20 7946 0958", "", "+442079460958", "GB", nil},
+		{"020 7946 0958", "GB", "+442079460958", "GB", nil},
+		{"+44 (0)20 7946 0958", "", "+442079460958", "GB", nil},
+		{"0044 7700 900123", "", "+447700900123", "GB", nil},
+		{"+44 7911 123456", "", "+447911123456", "GG", nil},
+		{"(201) 555-0123", "US", "+12015550123", "US", nil},
+		{"1-201-555-0123", "us", "+12015550123", "US", nil},
+		{"201.555.0123", "CA", "+12015550123", "US", nil},
+		{"011 44 20 7946 0958", "US", "+442079460958", "GB", nil},
+		{"+1 416 555 0199", "", "+14165550199", "CA", nil},
+		{"+1 787 555 0123", "", "+17875550123", "PR", nil},
+		{"+44 1624 123456", "", "+441624123456", "IM", nil},
+		{"+7 701 123 4567", "", "+77011234567", "KZ", nil},
+		{"8 (912) 345-67-89", "RU", "+79123456789", "RU", nil},
+		{"01512 3456789", "DE", "+4915123456789", "DE", nil},
+		{"+39 06 1234 5678", "", "+390612345678", "IT", nil},
+		{"+55 11 91234-5678", "", "+5511912345678", "BR", nil},
+		{"＋１ ２０１ ５５５ ０１２３", "", "+12015550123", "US", nil},
+		{"", "US", "", "", ErrPhoneRequired},
+		{"201 555 0123", "", "", "", ErrPhoneCountryRequired},
+		{"+1 201 555 0123 ext 4", "", "", "", ErrPhoneInvalidChar},
+		{"+999 123 4567", "", "", "", ErrPhoneInvalidCountryCode},
+		{"555 0123", "XX", "", "", ErrPhoneInvalidCountryCode},
+		{"+1", "", "", "", ErrPhoneTooShort},
+		{"+44 20 7946", "", "", "", ErrPhoneTooShort},
+		{"+44 20 7946 0958 12", "", "", "", ErrPhoneTooLong},
+		{"+1 123 555 0123", "", "", "", ErrPhoneInvalidNumber},
+		{"+39 4 1234 5678", "", "", "", ErrPhoneInvalidNumber},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParsePhone(tt.input, tt.region)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParsePhone(%q, %q) error = %v; want %v", tt.input, tt.region, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.E164() != tt.wantE164 || got.Region != tt.wantRegion {
+				t.Errorf("ParsePhone(%q, %q) = %s (%s); want %s (%s)", tt.input, tt.region, got, got.Region, tt.wantE164, tt.wantRegion)
+			}
+		})
+	}
+}
+
+func TestParsePhoneErrorParams(t *testing.T) {
+	tests := []struct {
+		input  string
+		code   string
+		params map[string]any
+	}{
+		{"+44 20 7946", "phone.too_short", map[string]any{"min": 9, "region": "GB"}},
+		{"+1 201 555 01234", "phone.too_long", map[string]any{"max": 10, "region": "US"}},
+		{"+999 123", "phone.invalid_country_code", map[string]any{"code": "999"}},
+		{"+1 (201) 555-O123", "phone.invalid_char", map[string]any{"char": "O"}},
+		{"+", "phone.country_required", nil},
+		{"00", "phone.country_required", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			fe := NewFieldError("Phone", tt.input, ValidatePhone(tt.input))
+			if fe.Code != tt.code {
+				t.Errorf("Code = %q; want %q", fe.Code, tt.code)
+			}
+			for name, want := range tt.params {
+				if got := fe.Params[name]; got != want {
+					t.Errorf("Params[%q] = %v; want %v", name, got, want)
+				}
+			}
+			if msg := DefaultCatalog.Translate("en", fe); strings.Contains(msg, "{") {
+				t.Errorf("message %q has an unfilled placeholder", msg)
+			}
+		})
+	}
+}
+
+func TestPhoneNumberFormat(t *testing.T) {
+	tests := []struct {
+		input         string
+		international string
+		national      string
+		rfc3966       string
+	}{
+		{"+442079460958", "+44 20 7946 0958", "020 7946 0958", "tel:+44-20-7946-0958"},
+		{"+447700900123", "+44 7700 900123", "07700 900123", "tel:+44-7700-900123"},
+		{"+12015550123", "+1 201-555-0123", "201-555-0123", "tel:+1-201-555-0123"},
+		{"+33123456789", "+33 1 23 45 67 89", "01 23 45 67 89", "tel:+33-1-23-45-67-89"},
+		{"+79123456789", "+7 912 345-67-89", "8 912 345-67-89", "tel:+7-912-345-67-89"},
+		{"+5511912345678", "+55 (11) 91234-5678", "(11) 91234-5678", "tel:+55-11-91234-5678"},
+		{"+819012345678", "+81 90-1234-5678", "090-1234-5678", "tel:+81-90-1234-5678"},
+		// No template for this length: the digits are kept together.
+		{"+4312345", "+43 12345", "012345", "tel:+43-12345"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			n, err := ParsePhone(tt.input, "")
+			if err != nil {
+				t.Fatalf("ParsePhone(%q) error = %v", tt.input, err)
+			}
+			if got := n.FormatInternational(); got != tt.international {
+				t.Errorf("FormatInternational() = %q; want %q", got, tt.international)
+			}
+			if got := n.FormatNational(); got != tt.national {
+				t.Errorf("FormatNational() = %q; want %q", got, tt.national)
+			}
+			if got := n.FormatRFC3966(); got != tt.rfc3966 {
+				t.Errorf("FormatRFC3966() = %q; want %q", got, tt.rfc3966)
+			}
+		})
+	}
+}
+
+func TestPhoneNumberFormatBuiltByHand(t *testing.T) {
+	n := PhoneNumber{CountryCode: 44, NationalNumber: "2079460958"}
+	if got, want := n.FormatNational(), "020 7946 0958"; got != want {
+		t.Errorf("FormatNational() = %q; want %q", got, want)
+	}
+	unknown := PhoneNumber{CountryCode: 888, NationalNumber: "12345678"}
+	if got, want := unknown.FormatInternational(), "+888 12345678"; got != want {
+		t.Errorf("FormatInternational() = %q; want %q", got, want)
+	}
+}
+
+func TestNormalizePhone(t *testing.T) {
+	got, err := NormalizePhone("(020) 7946-0958", "GB")
+	if err != nil || got != "+442079460958" {
+		t.Errorf("NormalizePhone = %q, %v; want %q, nil", got, err, "+442079460958")
+	}
+	if _, err := NormalizePhone("12", "GB"); !errors.Is(err, ErrPhoneTooShort) {
+		t.Errorf("NormalizePhone(12) error = %v; want ErrPhoneTooShort", err)
+	}
+}
+
+func TestPhoneRegionsLoad(t *testing.T) {
+	regions := NewPhoneRegions()
+	err := regions.Load(strings.NewReader(`# test plan
+ZZ ; 888 ; 0 ; 6,8 ; [1-9]\d{5}(?:\d{2})? ; 8:NXX XX XX XX ;
+ZY ; 888 ; 0 ; 6 ; 9\d{5} ; 6:NXXX XXX ; 9
+`))
+	if err != nil {
+		t.Fatalf("Load error = %v", err)
+	}
+	policy := PhonePolicy{DefaultRegion: "zz", Regions: regions}
+
+	tests := []struct {
+		input      string
+		wantRegion string
+		wantErr    error
+	}{
+		{"012 34 56 78", "ZZ", nil},
+		{"123456", "ZZ", nil},
+		{"0912345", "ZY", nil},
+		{"1234567", "", ErrPhoneInvalidNumber},
+		{"+888 123456789", "", ErrPhoneTooLong},
+		{"+44 20 7946 0958", "", ErrPhoneInvalidCountryCode},
+	}
+	for _, tt := range tests {
+		n, err := policy.Parse(tt.input)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("Parse(%q) error = %v; want %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if err == nil && n.Region != tt.wantRegion {
+			t.Errorf("Parse(%q) region = %q; want %q", tt.input, n.Region, tt.wantRegion)
+		}
+	}
+
+	for _, bad := range []string{
+		"ZZ ; 888 ; 0 ; 6",
+		"ZZZ ; 888 ; 0 ; 6 ; \\d+",
+		"ZZ ; 0 ; 0 ; 6 ; \\d+",
+		"ZZ ; 888 ; 0 ; 6-x ; \\d+",
+		"ZZ ; 888 ; 0 ; 14 ; \\d+",
+		"ZZ ; 888 ; 0 ; 6 ; [",
+		"ZZ ; 888 ; 0 ; 6 ; \\d+ ; 6:XXX",
+	} {
+		if err := NewPhoneRegions().Load(strings.NewReader(bad)); err == nil {
+			t.Errorf("Load(%q) returned nil error", bad)
+		}
+	}
+}
+
+func TestPhoneRegionsAddRejectsIncompleteRegions(t *testing.T) {
+	pattern := regexp.MustCompile(`^\d{6}$`)
+	regions := NewPhoneRegions()
+	for _, bad := range []*PhoneRegion{
+		{Region: "ZZ", CallingCode: 888, Pattern: pattern},
+		{Region: "ZZ", CallingCode: 888, Lengths: []int{8, 6}, Pattern: pattern},
+		{Region: "ZZ", CallingCode: 888, Lengths: []int{6}},
+		{Region: "ZZZ", CallingCode: 888, Lengths: []int{6}, Pattern: pattern},
+		{Region: "ZZ", Lengths: []int{6}, Pattern: pattern},
+	} {
+		if err := regions.Add(bad); err == nil {
+			t.Errorf("Add(%+v) returned nil error", bad)
+		}
+	}
+
+	policy := PhonePolicy{Regions: regions}
+	if _, err := policy.Parse("+888 123456"); !errors.Is(err, ErrPhoneInvalidCountryCode) {
+		t.Errorf("Parse after rejected Add error = %v; want ErrPhoneInvalidCountryCode", err)
+	}
+	if err := regions.Add(&PhoneRegion{Region: "zz", CallingCode: 888, Lengths: []int{6}, Pattern: pattern}); err != nil {
+		t.Fatalf("Add(valid) error = %v", err)
+	}
+	if n, err := policy.Parse("+888 123456"); err != nil || n.Region != "ZZ" {
+		t.Errorf("Parse = %v (%s), %v; want ZZ", n, n.Region, err)
+	}
+}
+
+func TestValidateUserPhone(t *testing.T) {
+	tests := []struct {
+		phone   string
+		wantErr error
+	}{
+		{"", nil},
+		{"+44 20 7946 0958", nil},
+		{"020 7946 0958", ErrPhoneCountryRequired},
+		{"+44 20", ErrPhoneTooShort},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.phone, func(t *testing.T) {
+			u := User{Username: "johndoe", Email: "john@example.com", Age: 30, Phone: tt.phone}
+			err := ValidateUser(u)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ValidateUser error = %v; want %v", err, tt.wantErr)
+			}
+			var errs ValidationErrors
+			if errors.As(err, &errs) && errs[0].Path != "Phone" {
+				t.Errorf("error path = %q; want Phone", errs[0].Path)
+			}
+		})
+	}
+}
+
+func TestValidateStructPhoneTag(t *testing.T) {
+	type contact struct {
+		Phone string `validate:"phone=GB"`
+	}
+	if err := ValidateStruct(contact{Phone: "020 7946 0958"}); err != nil {
+		t.Errorf("ValidateStruct(national number) = %v; want nil", err)
+	}
+	if err := ValidateStruct(contact{Phone: "020 7946"}); !errors.Is(err, ErrPhoneTooShort) {
+		t.Errorf("ValidateStruct(short number) = %v; want ErrPhoneTooShort", err)
+	}
+
+	type unknownRegion struct {
+		Phone string `validate:"phone=XX"`
+	}
+	if err := ValidateStruct(unknownRegion{}); !errors.Is(err, ErrInvalidTag) {
+		t.Errorf("ValidateStruct(unknown region) = %v; want ErrInvalidTag", err)
+	}
+}
@@ -0,0 +1,348 @@
+package basics
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidPolynomial = errors.New("invalid polynomial")
+	ErrInvalidBracket    = errors.New("bracket does not contain a sign change")
+	ErrNoConvergence     = errors.New("root finding did not converge")
+	ErrZeroDerivative    = errors.New("derivative is zero")
+)
+
+// maxPolynomialDegree bounds the degree of parsed and computed
+// polynomials, whose coefficients are stored densely.
+const maxPolynomialDegree = 10000
+
+// Polynomial holds coefficients in ascending order of degree, so
+// Polynomial{1, -2, 3} is 3x^2 - 2x + 1.
+type Polynomial []float64
+
+// ParsePolynomial parses expressions such as "3x^2 - 2x + 1". Terms may
+// appear in any order and repeated degrees are summed. Degrees above
+// maxPolynomialDegree are rejected.
+func ParsePolynomial(s string) (Polynomial, error) {
+	s = strings.ReplaceAll(s, " ", "")
+	if s == "" {
+		return nil, ErrInvalidPolynomial
+	}
+
+	var p Polynomial
+	for len(s) > 0 {
+		end := 1
+		for end < len(s) && (s[end] != '+' && s[end] != '-' || signInTerm(s, end)) {
+			end++
+		}
+		term := s[:end]
+		s = s[end:]
+
+		coef, degree, err := parseTerm(term)
+		if err != nil {
+			return nil, err
+		}
+		for len(p) <= degree {
+			p = append(p, 0)
+		}
+		p[degree] += coef
+	}
+
+	return p.trim(), nil
+}
+
+// signInTerm reports whether the sign at s[i] belongs to the current term
+// rather than starting a new one: it follows '^' in an exponent, or the 'e'
+// of a coefficient in exponent notation such as "1e-5".
+func signInTerm(s string, i int) bool {
+	switch s[i-1] {
+	case '^':
+		return true
+	case 'e', 'E':
+		return i >= 2 && ('0' <= s[i-2] && s[i-2] <= '9' || s[i-2] == '.')
+	}
+	return false
+}
+
+func parseTerm(term string) (float64, int, error) {
+	coefText, powerText, hasX := strings.Cut(term, "x")
+	coefText = strings.TrimSuffix(coefText, "*")
+
+	var coef float64
+	switch coefText {
+	case "", "+":
+		coef = 1
+	case "-":
+		coef = -1
+	default:
+		c, err := strconv.ParseFloat(coefText, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("%w: bad coefficient in %q", ErrInvalidPolynomial, term)
+		}
+		coef = c
+	}
+
+	if !hasX {
+		if coefText == "" || coefText == "+" || coefText == "-" {
+			return 0, 0, fmt.Errorf("%w: empty term", ErrInvalidPolynomial)
+		}
+		return coef, 0, nil
+	}
+
+	if powerText == "" {
+		return coef, 1, nil
+	}
+	if !strings.HasPrefix(powerText, "^") {
+		return 0, 0, fmt.Errorf("%w: unexpected %q in %q", ErrInvalidPolynomial, powerText, term)
+	}
+	degree, err := strconv.Atoi(powerText[1:])
+	if err != nil || degree < 0 {
+		return 0, 0, fmt.Errorf("%w: bad exponent in %q", ErrInvalidPolynomial, term)
+	}
+	if degree > maxPolynomialDegree {
+		return 0, 0, fmt.Errorf("%w: degree of %q exceeds %d", ErrInvalidPolynomial, term, maxPolynomialDegree)
+	}
+	return coef, degree, nil
+}
+
+// trim drops trailing zero coefficients so the slice length matches the
+// degree. The zero polynomial becomes an empty slice.
+func (p Polynomial) trim() Polynomial {
+	n := len(p)
+	for n > 0 && p[n-1] == 0 {
+		n--
+	}
+	return p[:n:n]
+}
+
+// Degree returns the degree of p, or -1 for the zero polynomial.
+func (p Polynomial) Degree() int {
+	return len(p.trim()) - 1
+}
+
+func (p Polynomial) Add(q Polynomial) Polynomial {
+	out := make(Polynomial, max(len(p), len(q)))
+	for i := range out {
+		if i < len(p) {
+			out[i] += p[i]
+		}
+		if i < len(q) {
+			out[i] += q[i]
+		}
+	}
+	return out.trim()
+}
+
+func (p Polynomial) Subtract(q Polynomial) Polynomial {
+	neg := make(Polynomial, len(q))
+	for i, c := range q {
+		neg[i] = -c
+	}
+	return p.Add(neg)
+}
+
+func (p Polynomial) Multiply(q Polynomial) Polynomial {
+	p, q = p.trim(), q.trim()
+	if len(p) == 0 || len(q) == 0 {
+		return Polynomial{}
+	}
+
+	out := make(Polynomial, len(p)+len(q)-1)
+	for i, a := range p {
+		for j, b := range q {
+			out[i+j] += a * b
+		}
+	}
+	return out.trim()
+}
+
+// Power raises p to a non-negative integer power by repeated squaring,
+// like Calculator.Power. Results of degree above maxPolynomialDegree
+// are rejected.
+func (p Polynomial) Power(exp int) (Polynomial, error) {
+	if exp < 0 {
+		return nil, ErrNegativeExponent
+	}
+	p = p.trim()
+	if degree := len(p) - 1; degree > 0 && exp > maxPolynomialDegree/degree {
+		return nil, fmt.Errorf("%w: degree of result exceeds %d", ErrInvalidPolynomial, maxPolynomialDegree)
+	}
+
+	return powerBySquaring(p, exp, Polynomial{1}, Polynomial.Multiply), nil
+}
+
+// Divide performs polynomial long division, returning the quotient and the
+// remainder such that p = q·quotient + remainder.
+func (p Polynomial) Divide(q Polynomial) (quotient, remainder Polynomial, err error) {
+	q = q.trim()
+	if len(q) == 0 {
+		return nil, nil, ErrDivisionByZero
+	}
+
+	remainder = append(Polynomial(nil), p.trim()...)
+	if len(remainder) < len(q) {
+		return Polynomial{}, remainder, nil
+	}
+
+	quotient = make(Polynomial, len(remainder)-len(q)+1)
+	lead := q[len(q)-1]
+	for i := len(quotient) - 1; i >= 0; i-- {
+		coef := remainder[i+len(q)-1] / lead
+		quotient[i] = coef
+		for j, c := range q {
+			remainder[i+j] -= coef * c
+		}
+		// Clear the eliminated term exactly to avoid rounding residue.
+		remainder[i+len(q)-1] = 0
+	}
+
+	return quotient.trim(), remainder.trim(), nil
+}
+
+// Evaluate computes p(x) using Horner's method.
+func (p Polynomial) Evaluate(x float64) float64 {
+	var result float64
+	for i := len(p) - 1; i >= 0; i-- {
+		result = result*x + p[i]
+	}
+	return result
+}
+
+func (p Polynomial) Derivative() Polynomial {
+	if len(p) <= 1 {
+		return Polynomial{}
+	}
+
+	out := make(Polynomial, len(p)-1)
+	for i := 1; i < len(p); i++ {
+		out[i-1] = p[i] * float64(i)
+	}
+	return out.trim()
+}
+
+func (p Polynomial) String() string {
+	p = p.trim()
+	if len(p) == 0 {
+		return "0"
+	}
+
+	var b strings.Builder
+	for degree := len(p) - 1; degree >= 0; degree-- {
+		coef := p[degree]
+		if coef == 0 {
+			continue
+		}
+
+		switch {
+		case b.Len() == 0 && coef < 0:
+			b.WriteString("-")
+		case b.Len() > 0 && coef < 0:
+			b.WriteString(" - ")
+		case b.Len() > 0:
+			b.WriteString(" + ")
+		}
+
+		abs := math.Abs(coef)
+		if abs != 1 || degree == 0 {
+			b.WriteString(formatReal(abs))
+		}
+		if degree >= 1 {
+			b.WriteString("x")
+		}
+		if degree > 1 {
+			b.WriteString("^")
+			b.WriteString(strconv.Itoa(degree))
+		}
+	}
+	return b.String()
+}
+
+// RootOptions controls the iterative root finders. Zero fields fall back to
+// DefaultRootOptions.
+type RootOptions struct {
+	Tolerance     float64
+	MaxIterations int
+}
+
+var DefaultRootOptions = RootOptions{
+	Tolerance:     1e-10,
+	MaxIterations: 100,
+}
+
+func (o RootOptions) withDefaults() RootOptions {
+	if o.Tolerance <= 0 {
+		o.Tolerance = DefaultRootOptions.Tolerance
+	}
+	if o.MaxIterations <= 0 {
+		o.MaxIterations = DefaultRootOptions.MaxIterations
+	}
+	return o
+}
+
+// NewtonRoot finds a root of p starting from x0 using Newton's method.
+func (p Polynomial) NewtonRoot(x0 float64, opts RootOptions) (float64, error) {
+	opts = opts.withDefaults()
+	derivative := p.Derivative()
+
+	x := x0
+	for i := 0; i < opts.MaxIterations; i++ {
+		fx := p.Evaluate(x)
+		if math.Abs(fx) <= opts.Tolerance {
+			return x, nil
+		}
+
+		dfx := derivative.Evaluate(x)
+		if dfx == 0 {
+			return 0, fmt.Errorf("%w at x = %g", ErrZeroDerivative, x)
+		}
+
+		next := x - fx/dfx
+		if math.IsNaN(next) || math.IsInf(next, 0) {
+			break
+		}
+		if math.Abs(next-x) <= opts.Tolerance {
+			return next, nil
+		}
+		x = next
+	}
+
+	return 0, fmt.Errorf("%w after %d iterations", ErrNoConvergence, opts.MaxIterations)
+}
+
+// BisectionRoot finds a root of p in [lo, hi]. p(lo) and p(hi) must have
+// opposite signs, or one of them must be zero.
+func (p Polynomial) BisectionRoot(lo, hi float64, opts RootOptions) (float64, error) {
+	opts = opts.withDefaults()
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+
+	flo, fhi := p.Evaluate(lo), p.Evaluate(hi)
+	switch {
+	case flo == 0:
+		return lo, nil
+	case fhi == 0:
+		return hi, nil
+	case math.Signbit(flo) == math.Signbit(fhi):
+		return 0, fmt.Errorf("%w: p(%g) = %g, p(%g) = %g", ErrInvalidBracket, lo, flo, hi, fhi)
+	}
+
+	for i := 0; i < opts.MaxIterations; i++ {
+		mid := lo + (hi-lo)/2
+		fmid := p.Evaluate(mid)
+		if fmid == 0 || (hi-lo)/2 <= opts.Tolerance {
+			return mid, nil
+		}
+
+		if math.Signbit(fmid) == math.Signbit(flo) {
+			lo, flo = mid, fmid
+		} else {
+			hi = mid
+		}
+	}
+
+	return 0, fmt.Errorf("%w after %d iterations", ErrNoConvergence, opts.MaxIterations)
+}
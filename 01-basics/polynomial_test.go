@@ -0,0 +1,184 @@
+package basics
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func polynomialsEqual(a, b Polynomial) bool {
+	a, b = a.trim(), b.trim()
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.Abs(a[i]-b[i]) > 1e-9 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestParsePolynomial(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Polynomial
+		wantErr error
+	}{
+		{"quadratic", "3x^2 - 2x + 1", Polynomial{1, -2, 3}, nil},
+		{"unordered terms", "1 + x^3", Polynomial{1, 0, 0, 1}, nil},
+		{"implicit coefficients", "-x^2 + x", Polynomial{0, 1, -1}, nil},
+		{"decimal coefficient", "0.5*x", Polynomial{0, 0.5}, nil},
+		{"exponent notation", "1e-5x + 2.5E+2", Polynomial{250, 1e-5}, nil},
+		{"exponent notation with power", "2.e3x^2 - 1", Polynomial{-1, 0, 2000}, nil},
+		{"repeated degree", "x + x", Polynomial{0, 2}, nil},
+		{"constant", "7", Polynomial{7}, nil},
+		{"cancels to zero", "x - x", Polynomial{}, nil},
+		{"empty", "", nil, ErrInvalidPolynomial},
+		{"bad coefficient", "3y^2", nil, ErrInvalidPolynomial},
+		{"negative exponent", "x^-1", nil, ErrInvalidPolynomial},
+		{"dangling sign", "x +", nil, ErrInvalidPolynomial},
+		{"huge degree", "x^1000000000", nil, ErrInvalidPolynomial},
+		{"degree overflows int", "x^99999999999999999999", nil, ErrInvalidPolynomial},
+		{"largest degree", "x^10000", append(make(Polynomial, 10000), 1), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePolynomial(tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParsePolynomial(%q) error = %v; want %v", tt.input, err, tt.wantErr)
+			}
+			if tt.wantErr == nil && !polynomialsEqual(got, tt.want) {
+				t.Errorf("ParsePolynomial(%q) = %v; want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolynomialString(t *testing.T) {
+	tests := []struct {
+		p    Polynomial
+		want string
+	}{
+		{Polynomial{1, -2, 3}, "3x^2 - 2x + 1"},
+		{Polynomial{0, 1, -1}, "-x^2 + x"},
+		{Polynomial{-4}, "-4"},
+		{Polynomial{}, "0"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.p.String(); got != tt.want {
+			t.Errorf("%v.String() = %q; want %q", []float64(tt.p), got, tt.want)
+		}
+	}
+}
+
+func TestPolynomialArithmetic(t *testing.T) {
+	p := Polynomial{1, 1}  // x + 1
+	q := Polynomial{-1, 1} // x - 1
+
+	if got, want := p.Add(q), (Polynomial{0, 2}); !polynomialsEqual(got, want) {
+		t.Errorf("Add = %v; want %v", got, want)
+	}
+	if got, want := p.Subtract(q), (Polynomial{2}); !polynomialsEqual(got, want) {
+		t.Errorf("Subtract = %v; want %v", got, want)
+	}
+	if got, want := p.Multiply(q), (Polynomial{-1, 0, 1}); !polynomialsEqual(got, want) {
+		t.Errorf("Multiply = %v; want %v", got, want)
+	}
+
+	squared, err := p.Power(2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := (Polynomial{1, 2, 1}); !polynomialsEqual(squared, want) {
+		t.Errorf("Power(2) = %v; want %v", squared, want)
+	}
+	if _, err := p.Power(-1); !errors.Is(err, ErrNegativeExponent) {
+		t.Errorf("Power(-1) error = %v; want ErrNegativeExponent", err)
+	}
+	if _, err := p.Power(1000000000); !errors.Is(err, ErrInvalidPolynomial) {
+		t.Errorf("Power(1e9) error = %v; want ErrInvalidPolynomial", err)
+	}
+	if got, err := (Polynomial{1}).Power(1000000000); err != nil || !polynomialsEqual(got, Polynomial{1}) {
+		t.Errorf("Polynomial{1}.Power(1e9) = %v, %v; want 1", got, err)
+	}
+}
+
+func TestPolynomialDivide(t *testing.T) {
+	// (x^3 - 2x^2 - 4) / (x - 3) = x^2 + x + 3 remainder 5
+	p := Polynomial{-4, 0, -2, 1}
+	q := Polynomial{-3, 1}
+
+	quotient, remainder, err := p.Divide(q)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := (Polynomial{3, 1, 1}); !polynomialsEqual(quotient, want) {
+		t.Errorf("quotient = %v; want %v", quotient, want)
+	}
+	if want := (Polynomial{5}); !polynomialsEqual(remainder, want) {
+		t.Errorf("remainder = %v; want %v", remainder, want)
+	}
+
+	if _, _, err := p.Divide(Polynomial{0}); !errors.Is(err, ErrDivisionByZero) {
+		t.Errorf("Divide by zero polynomial error = %v; want ErrDivisionByZero", err)
+	}
+}
+
+func TestPolynomialEvaluateAndDerivative(t *testing.T) {
+	p := Polynomial{1, -2, 3} // 3x^2 - 2x + 1
+
+	if got := p.Evaluate(2); got != 9 {
+		t.Errorf("Evaluate(2) = %g; want 9", got)
+	}
+	if got, want := p.Derivative(), (Polynomial{-2, 6}); !polynomialsEqual(got, want) {
+		t.Errorf("Derivative = %v; want %v", got, want)
+	}
+	if got := p.Degree(); got != 2 {
+		t.Errorf("Degree = %d; want 2", got)
+	}
+}
+
+func TestNewtonRoot(t *testing.T) {
+	p := Polynomial{-2, 0, 1} // x^2 - 2
+
+	root, err := p.NewtonRoot(1, RootOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(root-math.Sqrt2) > 1e-9 {
+		t.Errorf("NewtonRoot = %g; want %g", root, math.Sqrt2)
+	}
+
+	if _, err := p.NewtonRoot(0, RootOptions{}); !errors.Is(err, ErrZeroDerivative) {
+		t.Errorf("NewtonRoot at stationary point error = %v; want ErrZeroDerivative", err)
+	}
+
+	noRoot := Polynomial{1, 0, 1} // x^2 + 1
+	if _, err := noRoot.NewtonRoot(0.5, RootOptions{MaxIterations: 20}); !errors.Is(err, ErrNoConvergence) {
+		t.Errorf("NewtonRoot without real root error = %v; want ErrNoConvergence", err)
+	}
+}
+
+func TestBisectionRoot(t *testing.T) {
+	p := Polynomial{-2, 0, 1} // x^2 - 2
+
+	root, err := p.BisectionRoot(0, 2, RootOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(root-math.Sqrt2) > 1e-9 {
+		t.Errorf("BisectionRoot = %g; want %g", root, math.Sqrt2)
+	}
+
+	if _, err := p.BisectionRoot(2, 3, RootOptions{}); !errors.Is(err, ErrInvalidBracket) {
+		t.Errorf("BisectionRoot without sign change error = %v; want ErrInvalidBracket", err)
+	}
+
+	if _, err := p.BisectionRoot(0, 2, RootOptions{Tolerance: 1e-15, MaxIterations: 5}); !errors.Is(err, ErrNoConvergence) {
+		t.Errorf("BisectionRoot with few iterations error = %v; want ErrNoConvergence", err)
+	}
+}
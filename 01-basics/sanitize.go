@@ -0,0 +1,327 @@
+package basics
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+var ErrUnsafeChar = errors.New("text contains control or invisible characters")
+
+// UnsafeCharClass groups characters that are invisible or change how the
+// surrounding text is displayed.
+type UnsafeCharClass int
+
+const (
+	// CharControl covers C0 and C1 control characters, including tabs and
+	// line breaks, and bytes that are not valid UTF-8.
+	CharControl UnsafeCharClass = iota
+	// CharBidi covers the bidirectional marks, embeddings, overrides and
+	// isolates that can make text display in a different order than it is
+	// stored, such as U+202E RIGHT-TO-LEFT OVERRIDE.
+	CharBidi
+	// CharZeroWidth covers zero-width spaces and joiners and the byte order
+	// mark, which can hide inside otherwise identical names.
+	CharZeroWidth
+	// CharInvisible covers the remaining format characters, variation
+	// selectors and default-ignorable code points, such as the soft hyphen.
+	CharInvisible
+)
+
+var unsafeCharClassNames = [...]string{
+	CharControl:   "control",
+	CharBidi:      "bidi",
+	CharZeroWidth: "zero_width",
+	CharInvisible: "invisible",
+}
+
+func (c UnsafeCharClass) String() string {
+	if c < 0 || int(c) >= len(unsafeCharClassNames) {
+		return fmt.Sprintf("UnsafeCharClass(%d)", int(c))
+	}
+	return unsafeCharClassNames[c]
+}
+
+func (c UnsafeCharClass) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// CharAction says what a SanitizePolicy does with a class of characters.
+type CharAction int
+
+const (
+	// CharAllow keeps the characters. They are still reported.
+	CharAllow CharAction = iota
+	// CharStrip removes the characters.
+	CharStrip
+	// CharReject fails with ErrUnsafeChar.
+	CharReject
+)
+
+var charActionNames = [...]string{
+	CharAllow:  "allow",
+	CharStrip:  "strip",
+	CharReject: "reject",
+}
+
+func (a CharAction) String() string {
+	if a < 0 || int(a) >= len(charActionNames) {
+		return fmt.Sprintf("CharAction(%d)", int(a))
+	}
+	return charActionNames[a]
+}
+
+func (a CharAction) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnsafeChar is an unsafe character found in some text.
+type UnsafeChar struct {
+	// Offset is the character's byte offset in the original text.
+	Offset int
+	// Rune is the character, or utf8.RuneError for an invalid byte.
+	Rune   rune
+	Class  UnsafeCharClass
+	Action CharAction
+}
+
+// unsafeCharNames names the characters most often seen in spoofing
+// attempts, for audit logs.
+var unsafeCharNames = map[rune]string{
+	0x00AD: "SOFT HYPHEN",
+	0x061C: "ARABIC LETTER MARK",
+	0x180E: "MONGOLIAN VOWEL SEPARATOR",
+	0x200B: "ZERO WIDTH SPACE",
+	0x200C: "ZERO WIDTH NON-JOINER",
+	0x200D: "ZERO WIDTH JOINER",
+	0x200E: "LEFT-TO-RIGHT MARK",
+	0x200F: "RIGHT-TO-LEFT MARK",
+	0x202A: "LEFT-TO-RIGHT EMBEDDING",
+	0x202B: "RIGHT-TO-LEFT EMBEDDING",
+	0x202C: "POP DIRECTIONAL FORMATTING",
+	0x202D: "LEFT-TO-RIGHT OVERRIDE",
+	0x202E: "RIGHT-TO-LEFT OVERRIDE",
+	0x2060: "WORD JOINER",
+	0x2066: "LEFT-TO-RIGHT ISOLATE",
+	0x2067: "RIGHT-TO-LEFT ISOLATE",
+	0x2068: "FIRST STRONG ISOLATE",
+	0x2069: "POP DIRECTIONAL ISOLATE",
+	0xFEFF: "ZERO WIDTH NO-BREAK SPACE",
+}
+
+// CodePoint returns the character in U+XXXX notation.
+func (c UnsafeChar) CodePoint() string {
+	return fmt.Sprintf("%U", c.Rune)
+}
+
+// Name returns the character's Unicode name if it is a well-known unsafe
+// character, or "".
+func (c UnsafeChar) Name() string {
+	return unsafeCharNames[c.Rune]
+}
+
+// String describes the character, e.g. "U+202E RIGHT-TO-LEFT OVERRIDE".
+func (c UnsafeChar) String() string {
+	if name := c.Name(); name != "" {
+		return c.CodePoint() + " " + name
+	}
+	return c.CodePoint()
+}
+
+// MarshalJSON writes the character as its code point and name, since the
+// character itself would be invisible in an audit log.
+func (c UnsafeChar) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Offset    int             `json:"offset"`
+		CodePoint string          `json:"code_point"`
+		Name      string          `json:"name,omitempty"`
+		Class     UnsafeCharClass `json:"class"`
+		Action    CharAction      `json:"action"`
+	}{c.Offset, c.CodePoint(), c.Name(), c.Class, c.Action})
+}
+
+// ClassifyChar reports whether r is unsafe and, if so, its class.
+// Whitespace other than control characters is safe.
+func ClassifyChar(r rune) (UnsafeCharClass, bool) {
+	switch {
+	case unicode.Is(unicode.Cc, r):
+		return CharControl, true
+	case unicode.Is(unicode.Bidi_Control, r):
+		return CharBidi, true
+	case r == 0x200B || r == 0x2060 || r == 0xFEFF || r == 0x180E || unicode.Is(unicode.Join_Control, r):
+		return CharZeroWidth, true
+	case unicode.IsSpace(r):
+		return 0, false
+	case unicode.In(r, unicode.Cf, unicode.Variation_Selector, unicode.Other_Default_Ignorable_Code_Point):
+		return CharInvisible, true
+	}
+	return 0, false
+}
+
+// SanitizePolicy says what to do with each class of unsafe characters.
+// The zero value allows everything and only detects.
+type SanitizePolicy struct {
+	Control   CharAction
+	Bidi      CharAction
+	ZeroWidth CharAction
+	Invisible CharAction
+}
+
+var (
+	// StripUnsafeChars removes every unsafe character.
+	StripUnsafeChars = SanitizePolicy{Control: CharStrip, Bidi: CharStrip, ZeroWidth: CharStrip, Invisible: CharStrip}
+	// RejectUnsafeChars fails on any unsafe character.
+	RejectUnsafeChars = SanitizePolicy{Control: CharReject, Bidi: CharReject, ZeroWidth: CharReject, Invisible: CharReject}
+)
+
+func (p SanitizePolicy) action(class UnsafeCharClass) CharAction {
+	switch class {
+	case CharControl:
+		return p.Control
+	case CharBidi:
+		return p.Bidi
+	case CharZeroWidth:
+		return p.ZeroWidth
+	}
+	return p.Invisible
+}
+
+// Sanitize applies the policy to s. It returns the text with stripped
+// characters removed and every unsafe character found, whatever was done
+// with it. If any character is rejected, the text is "" and the error is
+// a *ParamError wrapping ErrUnsafeChar that names the first one.
+func (p SanitizePolicy) Sanitize(s string) (string, []UnsafeChar, error) {
+	var (
+		found    []UnsafeChar
+		rejected *UnsafeChar
+		b        strings.Builder
+	)
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		class, unsafe := ClassifyChar(r)
+		if r == utf8.RuneError && size == 1 {
+			class, unsafe = CharControl, true
+		}
+		if !unsafe {
+			b.WriteString(s[i : i+size])
+			i += size
+			continue
+		}
+
+		c := UnsafeChar{Offset: i, Rune: r, Class: class, Action: p.action(class)}
+		found = append(found, c)
+		switch c.Action {
+		case CharAllow:
+			b.WriteString(s[i : i+size])
+		case CharReject:
+			if rejected == nil {
+				rejected = &c
+			}
+		}
+		i += size
+	}
+
+	if rejected != nil {
+		return "", found, &ParamError{
+			Err:     ErrUnsafeChar,
+			Params:  map[string]any{"char": rejected.String(), "class": rejected.Class.String(), "offset": rejected.Offset},
+			Message: fmt.Sprintf("%v: %s at offset %d", ErrUnsafeChar, rejected, rejected.Offset),
+		}
+	}
+	if len(found) == 0 {
+		return s, nil, nil
+	}
+	return b.String(), found, nil
+}
+
+// Validate fails if s contains a character the policy rejects, so a
+// SanitizePolicy can be used as a Rule[string].
+func (p SanitizePolicy) Validate(s string) error {
+	_, _, err := p.Sanitize(s)
+	return err
+}
+
+// DetectUnsafeChars lists the unsafe characters in s without changing it.
+func DetectUnsafeChars(s string) []UnsafeChar {
+	_, found, _ := SanitizePolicy{}.Sanitize(s)
+	return found
+}
+
+// SanitizeReport records what sanitizing a field found, for auditing.
+type SanitizeReport struct {
+	Field string       `json:"field"`
+	Chars []UnsafeChar `json:"chars"`
+}
+
+// Removed returns the characters that were stripped.
+func (r SanitizeReport) Removed() []UnsafeChar {
+	var removed []UnsafeChar
+	for _, c := range r.Chars {
+		if c.Action == CharStrip {
+			removed = append(removed, c)
+		}
+	}
+	return removed
+}
+
+// Sanitizer applies a SanitizePolicy chosen by field name.
+type Sanitizer struct {
+	mu       sync.RWMutex
+	policies map[string]SanitizePolicy
+	// Default applies to fields without a policy of their own.
+	Default SanitizePolicy
+}
+
+// NewSanitizer returns a sanitizer applying def to every field until
+// SetPolicy says otherwise.
+func NewSanitizer(def SanitizePolicy) *Sanitizer {
+	return &Sanitizer{policies: make(map[string]SanitizePolicy), Default: def}
+}
+
+// SetPolicy sets the policy for field, replacing any existing one.
+func (s *Sanitizer) SetPolicy(field string, p SanitizePolicy) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.policies[field] = p
+}
+
+// Policy returns the policy applied to field.
+func (s *Sanitizer) Policy(field string) SanitizePolicy {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if p, ok := s.policies[field]; ok {
+		return p
+	}
+	return s.Default
+}
+
+// Sanitize applies field's policy to value. The report lists what was
+// found even when the value is rejected, in which case the error is a
+// *FieldError for field. The rejected value is not copied into the error,
+// as it may be a password.
+func (s *Sanitizer) Sanitize(field, value string) (string, SanitizeReport, error) {
+	clean, found, err := s.Policy(field).Sanitize(value)
+	report := SanitizeReport{Field: field, Chars: found}
+	if err != nil {
+		return "", report, NewFieldError(field, nil, err)
+	}
+	return clean, report, nil
+}
+
+// DefaultSanitizer is used by SanitizeUserInput. Usernames have bidi and
+// control characters rejected, since they are used for spoofing, and
+// zero-width and other invisible characters stripped, as NormalizeUsername
+// ignores them anyway. Email addresses have stray control characters, such
+// as a pasted line break, stripped and everything else rejected, so the
+// address mail is sent to is the one the user saw. Other fields are
+// stripped.
+var DefaultSanitizer = func() *Sanitizer {
+	s := NewSanitizer(StripUnsafeChars)
+	s.SetPolicy("Username", SanitizePolicy{Control: CharReject, Bidi: CharReject, ZeroWidth: CharStrip, Invisible: CharStrip})
+	s.SetPolicy("Email", SanitizePolicy{Control: CharStrip, Bidi: CharReject, ZeroWidth: CharReject, Invisible: CharReject})
+	return s
+}()
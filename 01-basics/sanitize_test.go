@@ -0,0 +1,241 @@
+package basics
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestClassifyChar(t *testing.T) {
+	tests := []struct {
+		r      rune
+		want   UnsafeCharClass
+		unsafe bool
+	}{
+		{'a', 0, false},
+		{' ', 0, false},
+		{'\u3000', 0, false},
+		{'é', 0, false},
+		{'\x00', CharControl, true},
+		{'\n', CharControl, true},
+		{'\u0085', CharControl, true},
+		{'\u202e', CharBidi, true},
+		{'\u2066', CharBidi, true},
+		{'\u200f', CharBidi, true},
+		{'\u061c', CharBidi, true},
+		{'\u200b', CharZeroWidth, true},
+		{'\u200d', CharZeroWidth, true},
+		{'\ufeff', CharZeroWidth, true},
+		{'\u00ad', CharInvisible, true},
+		{'\ufe0f', CharInvisible, true},
+		{'\U000e0041', CharInvisible, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.r), func(t *testing.T) {
+			got, unsafe := ClassifyChar(tt.r)
+			if unsafe != tt.unsafe || got != tt.want {
+				t.Errorf("ClassifyChar(%U) = %v, %t; want %v, %t", tt.r, got, unsafe, tt.want, tt.unsafe)
+			}
+		})
+	}
+}
+
+func TestSanitizePolicySanitize(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  SanitizePolicy
+		input   string
+		want    string
+		found   int
+		wantErr error
+	}{
+		{"clean", RejectUnsafeChars, "jane_doe", "jane_doe", 0, nil},
+		{"strip all", StripUnsafeChars, "ja\u200bne\u202e\x07doe\u00ad", "janedoe", 4, nil},
+		{"reject bidi", RejectUnsafeChars, "admin\u202egnp.exe", "", 1, ErrUnsafeChar},
+		{"allow detects", SanitizePolicy{}, "jo\u200dhn", "jo\u200dhn", 1, nil},
+		{"strip zero width only", SanitizePolicy{ZeroWidth: CharStrip, Bidi: CharReject}, "jo\u200chn\u00ad", "john\u00ad", 2, nil},
+		{"reject after strip", SanitizePolicy{ZeroWidth: CharStrip, Bidi: CharReject}, "jo\u200bhn\u2067", "", 2, ErrUnsafeChar},
+		{"invalid utf-8", StripUnsafeChars, "jo\xffhn", "john", 1, nil},
+		{"empty", RejectUnsafeChars, "", "", 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found, err := tt.policy.Sanitize(tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Sanitize(%q) error = %v; want %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Sanitize(%q) = %q; want %q", tt.input, got, tt.want)
+			}
+			if len(found) != tt.found {
+				t.Errorf("Sanitize(%q) found %v; want %d characters", tt.input, found, tt.found)
+			}
+		})
+	}
+}
+
+func TestSanitizeReportsOffsets(t *testing.T) {
+	input := "é\u202eab\u200bc"
+	_, found, _ := StripUnsafeChars.Sanitize(input)
+	want := []UnsafeChar{
+		{Offset: 2, Rune: '\u202e', Class: CharBidi, Action: CharStrip},
+		{Offset: 7, Rune: '\u200b', Class: CharZeroWidth, Action: CharStrip},
+	}
+	if len(found) != len(want) {
+		t.Fatalf("found %v; want %v", found, want)
+	}
+	for i := range want {
+		if found[i] != want[i] {
+			t.Errorf("found[%d] = %+v; want %+v", i, found[i], want[i])
+		}
+		if r, _ := utf8.DecodeRuneInString(input[found[i].Offset:]); r != found[i].Rune {
+			t.Errorf("found[%d].Offset points at %U; want %U", i, r, found[i].Rune)
+		}
+	}
+}
+
+func TestSanitizeRejectError(t *testing.T) {
+	_, _, err := RejectUnsafeChars.Sanitize("ab\u202ec")
+	var pe *ParamError
+	if !errors.As(err, &pe) {
+		t.Fatalf("error = %v; want a *ParamError", err)
+	}
+	if got, want := pe.Params["char"], "U+202E RIGHT-TO-LEFT OVERRIDE"; got != want {
+		t.Errorf("char = %v; want %q", got, want)
+	}
+	if got, want := pe.Params["offset"], 2; got != want {
+		t.Errorf("offset = %v; want %d", got, want)
+	}
+	if got := ErrorCode(err); got != "unsafe_char" {
+		t.Errorf("ErrorCode = %q; want %q", got, "unsafe_char")
+	}
+}
+
+func TestSanitizePolicyAsRule(t *testing.T) {
+	rules := RuleSet[string]{SanitizePolicy{Bidi: CharReject, ZeroWidth: CharStrip}}
+	if err := rules.Validate("jo\u200bhn"); err != nil {
+		t.Errorf("Validate(stripped char) = %v; want nil", err)
+	}
+	if err := rules.Validate("jo\u202ehn"); !errors.Is(err, ErrUnsafeChar) {
+		t.Errorf("Validate(rejected char) = %v; want ErrUnsafeChar", err)
+	}
+}
+
+func TestDetectUnsafeChars(t *testing.T) {
+	found := DetectUnsafeChars("a\u200db\u202ec")
+	if len(found) != 2 || found[0].Action != CharAllow || found[1].Class != CharBidi {
+		t.Errorf("DetectUnsafeChars = %v; want a zero-width and a bidi character, allowed", found)
+	}
+	if found := DetectUnsafeChars("plain"); found != nil {
+		t.Errorf("DetectUnsafeChars(plain) = %v; want nil", found)
+	}
+}
+
+func TestSanitizerPerFieldPolicies(t *testing.T) {
+	s := NewSanitizer(StripUnsafeChars)
+	s.SetPolicy("Username", RejectUnsafeChars)
+
+	got, report, err := s.Sanitize("Bio", "hi\u202ethere")
+	if err != nil || got != "hithere" {
+		t.Errorf("Sanitize(Bio) = %q, %v; want %q, nil", got, err, "hithere")
+	}
+	if removed := report.Removed(); len(removed) != 1 || removed[0].Rune != '\u202e' {
+		t.Errorf("Removed = %v; want the override", removed)
+	}
+
+	_, report, err = s.Sanitize("Username", "hi\u202ethere")
+	var fe *FieldError
+	if !errors.As(err, &fe) || fe.Path != "Username" || !errors.Is(err, ErrUnsafeChar) {
+		t.Fatalf("Sanitize(Username) error = %v; want FieldError for Username wrapping ErrUnsafeChar", err)
+	}
+	if fe.Value != nil {
+		t.Errorf("FieldError.Value = %v; want nil", fe.Value)
+	}
+	if len(report.Chars) != 1 || len(report.Removed()) != 0 {
+		t.Errorf("report = %+v; want one rejected character", report)
+	}
+}
+
+func TestSanitizeReportJSON(t *testing.T) {
+	_, report, _ := NewSanitizer(StripUnsafeChars).Sanitize("Username", "a\u200bb")
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"field":"Username","chars":[{"offset":1,"code_point":"U+200B","name":"ZERO WIDTH SPACE","class":"zero_width","action":"strip"}]}`
+	if string(data) != want {
+		t.Errorf("json = %s; want %s", data, want)
+	}
+}
+
+func TestSanitizeUserInput(t *testing.T) {
+	tests := []struct {
+		name      string
+		user      User
+		want      User
+		reports   int
+		errFields []string
+	}{
+		{
+			name:    "clean",
+			user:    User{Username: "jane_doe", Email: "jane@example.com"},
+			want:    User{Username: "jane_doe", Email: "jane@example.com"},
+			reports: 0,
+		},
+		{
+			name:    "zero width stripped",
+			user:    User{Username: "ja\u200bne", Email: "jane@example.com\n"},
+			want:    User{Username: "jane", Email: "jane@example.com"},
+			reports: 2,
+		},
+		{
+			name:      "bidi rejected",
+			user:      User{Username: "evil\u202egnp", Email: "e\u200d@example.com"},
+			want:      User{Username: "evil\u202egnp", Email: "e\u200d@example.com"},
+			reports:   2,
+			errFields: []string{"Username", "Email"},
+		},
+		{
+			name:    "phone stripped",
+			user:    User{Username: "jane", Email: "jane@example.com", Phone: "\u202a+44 20 7946 0958\u202c"},
+			want:    User{Username: "jane", Email: "jane@example.com", Phone: "+44 20 7946 0958"},
+			reports: 1,
+		},
+		{
+			name:    "password untouched",
+			user:    User{Username: "jane", Email: "jane@example.com", Password: "pa\u200bss"},
+			want:    User{Username: "jane", Email: "jane@example.com", Password: "pa\u200bss"},
+			reports: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reports, err := SanitizeUserInput(tt.user)
+			if got != tt.want {
+				t.Errorf("SanitizeUserInput() user = %+v; want %+v", got, tt.want)
+			}
+			if len(reports) != tt.reports {
+				t.Errorf("SanitizeUserInput() reports = %v; want %d", reports, tt.reports)
+			}
+			var errs ValidationErrors
+			if len(tt.errFields) == 0 {
+				if err != nil {
+					t.Errorf("SanitizeUserInput() error = %v; want nil", err)
+				}
+				return
+			}
+			if !errors.As(err, &errs) || len(errs) != len(tt.errFields) {
+				t.Fatalf("SanitizeUserInput() error = %v; want errors for %v", err, tt.errFields)
+			}
+			for i, field := range tt.errFields {
+				if errs[i].Path != field || errs[i].Code != "unsafe_char" {
+					t.Errorf("errs[%d] = %s (%s); want %s (unsafe_char)", i, errs[i].Path, errs[i].Code, field)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,551 @@
+package basics
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JSONSchemaDialect is the $schema of generated documents.
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema (draft 2020-12) document or subschema. Only the
+// keywords produced by the generator are modelled.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	ID                   string             `json:"$id,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	MinProperties        *int               `json:"minProperties,omitempty"`
+	MaxProperties        *int               `json:"maxProperties,omitempty"`
+	WriteOnly            bool               `json:"writeOnly,omitempty"`
+	// Nullable also allows null, which json.Marshal writes for nil slices,
+	// maps and pointers. It is written as a list of types, such as
+	// ["array", "null"], or as an anyOf for a $ref.
+	Nullable bool `json:"-"`
+	// ErrorMessage maps keywords to the message shown when they fail, in
+	// the format of the ajv-errors package. The value for "required" maps
+	// property names to messages.
+	ErrorMessage map[string]any     `json:"errorMessage,omitempty"`
+	Defs         map[string]*Schema `json:"$defs,omitempty"`
+
+	codes map[string]schemaCode
+}
+
+type schemaCode struct {
+	code   string
+	params map[string]any
+}
+
+// SetErrorCode records the error code, and its message parameters, that the
+// server reports when keyword fails. The generator turns it into an
+// ErrorMessage annotation in the requested locale.
+func (s *Schema) SetErrorCode(keyword, code string, params map[string]any) {
+	if s.codes == nil {
+		s.codes = make(map[string]schemaCode)
+	}
+	s.codes[keyword] = schemaCode{code, params}
+}
+
+// MarshalJSON writes the schema, spelling out Nullable, which has no keyword
+// of its own.
+func (s Schema) MarshalJSON() ([]byte, error) {
+	type plain Schema
+	if !s.Nullable || s.Type == "" && s.Ref == "" {
+		return json.Marshal(plain(s))
+	}
+	out := struct {
+		plain
+		Ref   string    `json:"$ref,omitempty"`
+		Type  any       `json:"type,omitempty"`
+		Enum  []any     `json:"enum,omitempty"`
+		AnyOf []*Schema `json:"anyOf,omitempty"`
+	}{plain: plain(s)}
+	if s.Ref != "" {
+		out.AnyOf = []*Schema{{Ref: s.Ref}, {Type: "null"}}
+	} else {
+		out.Type = []string{s.Type, "null"}
+	}
+	if len(s.Enum) > 0 {
+		out.Enum = append(slices.Clip(s.Enum), nil)
+	}
+	return json.Marshal(out)
+}
+
+// TagSchemaFunc describes a tag rule in JSON Schema by setting keywords on
+// the schema of the field it is used on. t is the field's type with
+// pointers removed.
+type TagSchemaFunc func(s *Schema, t reflect.Type, param string)
+
+// SchemaOptions controls JSON Schema generation.
+type SchemaOptions struct {
+	// ID is the document's $id, if any.
+	ID string
+	// Translator renders error message annotations. Nil means
+	// DefaultCatalog.
+	Translator Translator
+	// Locale of the messages. Empty means "en".
+	Locale string
+}
+
+// JSONSchema generates a JSON Schema for v's type with the default
+// struct validator and English messages.
+func JSONSchema(v any) (*Schema, error) {
+	return defaultStructValidator.JSONSchema(v, SchemaOptions{})
+}
+
+// JSONSchema generates a JSON Schema (draft 2020-12) describing the struct
+// type of v and the constraints of its `validate` tags, so that clients can
+// check input with the same rules as the server. Nested structs are placed
+// in $defs. Fields tagged omitempty are optional properties, so clients
+// should leave them out rather than send zero values. Slices, maps and
+// pointers also accept null, as json.Marshal writes for nil values, unless
+// they are tagged required. Rules without a schema
+// description, such as custom rules registered without RegisterRuleSchema,
+// are only enforced by the server.
+func (sv *StructValidator) JSONSchema(v any, opts SchemaOptions) (*Schema, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: %T is not a struct", ErrInvalidTag, v)
+	}
+	if opts.Translator == nil {
+		opts.Translator = DefaultCatalog
+	}
+	if opts.Locale == "" {
+		opts.Locale = "en"
+	}
+
+	g := &schemaGenerator{sv: sv, opts: opts, root: t, defs: make(map[string]*Schema), names: make(map[reflect.Type]string)}
+	root, err := g.structSchema(t)
+	if err != nil {
+		return nil, err
+	}
+	root.Schema = JSONSchemaDialect
+	root.ID = opts.ID
+	root.Title = t.Name()
+	if len(g.defs) > 0 {
+		root.Defs = g.defs
+	}
+	g.annotate(root)
+	return root, nil
+}
+
+type schemaGenerator struct {
+	sv    *StructValidator
+	opts  SchemaOptions
+	root  reflect.Type
+	defs  map[string]*Schema
+	names map[reflect.Type]string
+}
+
+func (g *schemaGenerator) structSchema(t reflect.Type) (*Schema, error) {
+	info, err := g.sv.structInfo(t)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for _, field := range info.fields {
+		sf := t.Field(field.index)
+		name := field.name
+		if tag, _, _ := strings.Cut(sf.Tag.Get("json"), ","); tag == "-" {
+			continue
+		} else if tag != "" {
+			name = tag
+		}
+
+		ft := sf.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		prop, err := g.typeSchema(sf.Type)
+		if err != nil {
+			return nil, err
+		}
+		for _, rule := range field.rules {
+			if rule.name == "required" {
+				s.Required = append(s.Required, name)
+				prop.Nullable = false
+				// The rule also rejects empty strings.
+				if ft.Kind() == reflect.String {
+					prop.MinLength = schemaPtr(1)
+					prop.SetErrorCode("minLength", ErrorCode(ErrRequired), nil)
+				}
+				continue
+			}
+			g.sv.mu.RLock()
+			describe := g.sv.schemas[rule.name]
+			g.sv.mu.RUnlock()
+			if describe != nil {
+				describe(prop, ft, rule.param)
+			}
+		}
+		s.Properties[name] = prop
+	}
+	return s, nil
+}
+
+var timeType = reflect.TypeFor[time.Time]()
+
+func (g *schemaGenerator) typeSchema(t reflect.Type) (*Schema, error) {
+	if t.Kind() == reflect.Pointer {
+		s, err := g.typeSchema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		s.Nullable = true
+		return s, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if t == timeType {
+			return &Schema{Type: "string", Format: "date-time"}, nil
+		}
+		if t == g.root {
+			return &Schema{Ref: "#"}, nil
+		}
+		name, ok := g.names[t]
+		if !ok {
+			name = g.defName(t)
+			g.names[t] = name
+			// Register before recursing so self-references terminate.
+			g.defs[name] = &Schema{}
+			def, err := g.structSchema(t)
+			if err != nil {
+				return nil, err
+			}
+			*g.defs[name] = *def
+		}
+		return &Schema{Ref: "#/$defs/" + name}, nil
+	case reflect.String:
+		return &Schema{Type: "string"}, nil
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &Schema{Type: "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}, nil
+	case reflect.Slice, reflect.Array:
+		items, err := g.typeSchema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "array", Items: items, Nullable: t.Kind() == reflect.Slice}, nil
+	case reflect.Map:
+		values, err := g.typeSchema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "object", AdditionalProperties: values, Nullable: true}, nil
+	}
+	// Interfaces and other kinds accept anything.
+	return &Schema{}, nil
+}
+
+// defName names a type in $defs, disambiguating types from different
+// packages that share a name.
+func (g *schemaGenerator) defName(t reflect.Type) string {
+	name := t.Name()
+	if name == "" {
+		name = "Struct"
+	}
+	for n := 2; ; n++ {
+		if _, taken := g.defs[name]; !taken {
+			return name
+		}
+		name = t.Name() + strconv.Itoa(n)
+	}
+}
+
+// annotate turns recorded error codes into ErrorMessage annotations,
+// throughout s.
+func (g *schemaGenerator) annotate(s *Schema) {
+	if s == nil {
+		return
+	}
+	for keyword, c := range s.codes {
+		g.setMessage(s, keyword, g.message(c.code, c.params))
+	}
+	if len(s.Required) > 0 {
+		required := make(map[string]string, len(s.Required))
+		for _, name := range s.Required {
+			required[name] = g.message(ErrorCode(ErrRequired), nil)
+		}
+		g.setMessage(s, "required", required)
+	}
+	for _, prop := range s.Properties {
+		g.annotate(prop)
+	}
+	for _, def := range s.Defs {
+		g.annotate(def)
+	}
+	g.annotate(s.Items)
+	g.annotate(s.AdditionalProperties)
+}
+
+func (g *schemaGenerator) setMessage(s *Schema, keyword string, message any) {
+	if s.ErrorMessage == nil {
+		s.ErrorMessage = make(map[string]any)
+	}
+	s.ErrorMessage[keyword] = message
+}
+
+func (g *schemaGenerator) message(code string, params map[string]any) string {
+	return g.opts.Translator.Translate(g.opts.Locale, &FieldError{Code: code, Message: code, Params: params})
+}
+
+func schemaPtr[T any](v T) *T {
+	return &v
+}
+
+// schemaBound describes min (lower) or max on a field of type t, with the
+// same meaning as the rules: a character count for strings, a length for
+// collections and a value for numbers.
+func schemaBound(s *Schema, t reflect.Type, param string, lower bool) {
+	limit, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+	size := int(math.Ceil(limit))
+	if !lower {
+		size = int(math.Floor(limit))
+	}
+
+	var keyword string
+	tooShort, tooLong := ErrTooShort, ErrTooLong
+	switch t.Kind() {
+	case reflect.String:
+		keyword = "maxLength"
+		if lower {
+			keyword = "minLength"
+			s.MinLength = schemaPtr(size)
+		} else {
+			s.MaxLength = schemaPtr(size)
+		}
+	case reflect.Slice, reflect.Array:
+		tooShort, tooLong = ErrTooFewItems, ErrTooManyItems
+		keyword = "maxItems"
+		if lower {
+			keyword = "minItems"
+			s.MinItems = schemaPtr(size)
+		} else {
+			s.MaxItems = schemaPtr(size)
+		}
+	case reflect.Map:
+		tooShort, tooLong = ErrTooFewItems, ErrTooManyItems
+		keyword = "maxProperties"
+		if lower {
+			keyword = "minProperties"
+			s.MinProperties = schemaPtr(size)
+		} else {
+			s.MaxProperties = schemaPtr(size)
+		}
+	default:
+		if _, isSize, ok := measure(reflect.Zero(t)); !ok || isSize {
+			return
+		}
+		keyword = "maximum"
+		if lower {
+			keyword = "minimum"
+			s.Minimum = schemaPtr(limit)
+		} else {
+			s.Maximum = schemaPtr(limit)
+		}
+		if lower {
+			s.SetErrorCode(keyword, ErrorCode(ErrTooSmall), map[string]any{"min": param})
+		} else {
+			s.SetErrorCode(keyword, ErrorCode(ErrTooLarge), map[string]any{"max": param})
+		}
+		return
+	}
+	if lower {
+		s.SetErrorCode(keyword, ErrorCode(tooShort), map[string]any{"min": param})
+	} else {
+		s.SetErrorCode(keyword, ErrorCode(tooLong), map[string]any{"max": param})
+	}
+}
+
+func schemaMin(s *Schema, t reflect.Type, param string) {
+	schemaBound(s, t, param, true)
+}
+
+func schemaMax(s *Schema, t reflect.Type, param string) {
+	schemaBound(s, t, param, false)
+}
+
+// schemaAge describes the bounds of AgeRules, with the error code it
+// reports.
+func schemaAge(s *Schema, _ reflect.Type, _ string) {
+	s.Minimum = schemaPtr(float64(minAge))
+	s.Maximum = schemaPtr(float64(maxAge))
+	params := map[string]any{"min": minAge, "max": maxAge}
+	s.SetErrorCode("minimum", ErrorCode(ErrInvalidAge), params)
+	s.SetErrorCode("maximum", ErrorCode(ErrInvalidAge), params)
+}
+
+func schemaLen(s *Schema, t reflect.Type, param string) {
+	schemaBound(s, t, param, true)
+	schemaBound(s, t, param, false)
+}
+
+// schemaOneOf lists the allowed values as an enum, typed to match the
+// field.
+func schemaOneOf(s *Schema, t reflect.Type, param string) {
+	words := strings.Fields(param)
+	s.Enum = nil
+	for _, w := range words {
+		var value any = w
+		switch s.Type {
+		case "integer", "number":
+			if f, err := strconv.ParseFloat(w, 64); err == nil {
+				value = f
+			}
+		case "boolean":
+			if b, err := strconv.ParseBool(w); err == nil {
+				value = b
+			}
+		}
+		s.Enum = append(s.Enum, value)
+	}
+	s.SetErrorCode("enum", ErrorCode(ErrNotOneOf), map[string]any{"allowed": strings.Join(words, ", ")})
+}
+
+// schemaPolicy adapts a policy's ApplySchema method to a TagSchemaFunc.
+func schemaPolicy(apply func(*Schema)) TagSchemaFunc {
+	return func(s *Schema, _ reflect.Type, _ string) {
+		apply(s)
+	}
+}
+
+// ApplySchema describes the policy's length and character rules. Reserved
+// names, blocked terms and script mixing are only checked by the server.
+func (p UsernamePolicy) ApplySchema(s *Schema) {
+	if p.MinLength > 0 {
+		s.MinLength = schemaPtr(p.MinLength)
+		s.SetErrorCode("minLength", ErrorCode(ErrUsernameTooShort), map[string]any{"min": p.MinLength})
+	}
+	if p.MaxLength > 0 {
+		s.MaxLength = schemaPtr(p.MaxLength)
+		s.SetErrorCode("maxLength", ErrorCode(ErrUsernameTooLong), map[string]any{"max": p.MaxLength})
+	}
+	if pattern, ok := p.schemaPattern(); ok {
+		s.Pattern = pattern
+		s.SetErrorCode("pattern", "username.pattern", nil)
+	}
+}
+
+// schemaPattern builds an ECMA-262 regular expression matching the
+// policy's character rules. It is unavailable for custom AllowedChars
+// without an AllowedCharsClass.
+func (p UsernamePolicy) schemaPattern() (string, bool) {
+	allowed := p.AllowedCharsClass
+	if allowed == "" {
+		if p.AllowedChars != nil {
+			return "", false
+		}
+		allowed = `\p{L}\p{N}`
+	}
+
+	var seps strings.Builder
+	for _, r := range p.Separators {
+		if strings.ContainsRune(`\]^-[`, r) {
+			seps.WriteByte('\\')
+		}
+		seps.WriteRune(r)
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	if p.MustStartWithLetter {
+		b.WriteString(`(?=\p{L})`)
+	}
+	switch {
+	case seps.Len() == 0:
+		b.WriteString("[" + allowed + "]*")
+	case p.NoConsecutiveSeparators:
+		b.WriteString("(?:[" + seps.String() + "]?[" + allowed + "])*[" + seps.String() + "]?")
+	default:
+		b.WriteString("[" + allowed + seps.String() + "]*")
+	}
+	b.WriteString("$")
+	return b.String(), true
+}
+
+// ApplySchema describes the policy as an email format and length limit.
+// Risk checks are only done by the server.
+func (p EmailPolicy) ApplySchema(s *Schema) {
+	s.Format = "idn-email"
+	if p.ASCIIOnly {
+		s.Format = "email"
+	}
+	s.SetErrorCode("format", ErrorCode(ErrInvalidEmail), nil)
+	s.MaxLength = schemaPtr(maxAddressLength)
+	s.SetErrorCode("maxLength", ErrorCode(ErrEmailTooLong), nil)
+}
+
+// ApplySchema describes the policy's length and character class rules and
+// marks the field write-only. The common-password, identity and strength
+// checks are only done by the server.
+func (p PasswordPolicy) ApplySchema(s *Schema) {
+	s.WriteOnly = true
+	if p.MinLength > 0 {
+		s.MinLength = schemaPtr(p.MinLength)
+		s.SetErrorCode("minLength", ErrorCode(ErrPasswordTooShort), map[string]any{"min": p.MinLength})
+	}
+	if p.MaxLength > 0 {
+		s.MaxLength = schemaPtr(p.MaxLength)
+		s.SetErrorCode("maxLength", ErrorCode(ErrPasswordTooLong), map[string]any{"max": p.MaxLength})
+	}
+
+	var lookaheads, classes []string
+	for _, c := range []struct {
+		required bool
+		name     string
+		class    string
+	}{
+		{p.RequireLower, "lowercase", `\p{Ll}`},
+		{p.RequireUpper, "uppercase", `\p{Lu}`},
+		{p.RequireDigit, "digit", `\p{Nd}`},
+		{p.RequireSymbol, "symbol", `[\p{P}\p{S} ]`},
+	} {
+		if c.required {
+			lookaheads = append(lookaheads, "(?=.*"+c.class+")")
+			classes = append(classes, c.name)
+		}
+	}
+	if len(lookaheads) > 0 {
+		s.Pattern = "^" + strings.Join(lookaheads, "")
+		s.SetErrorCode("pattern", "password.pattern", map[string]any{"classes": strings.Join(classes, ", ")})
+	}
+}
+
+// ApplySchema describes the characters a phone number may be written with.
+// Country codes, lengths and number ranges are only checked by the server.
+func (p PhonePolicy) ApplySchema(s *Schema) {
+	s.Pattern = `^\+?[0-9 ().\/-]+$`
+	s.SetErrorCode("pattern", "phone.pattern", nil)
+}
@@ -0,0 +1,454 @@
+package basics
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestJSONSchemaUser(t *testing.T) {
+	s, err := JSONSchema(&User{})
+	if err != nil {
+		t.Fatalf("JSONSchema error = %v", err)
+	}
+	if s.Schema != JSONSchemaDialect || s.Type != "object" || s.Title != "User" {
+		t.Errorf("root = {%q %q %q}; want a draft 2020-12 object titled User", s.Schema, s.Type, s.Title)
+	}
+	if !reflect.DeepEqual(s.Required, []string{"Username", "Email"}) {
+		t.Errorf("Required = %v; want [Username Email]", s.Required)
+	}
+
+	username := s.Properties["Username"]
+	if *username.MinLength != DefaultUsernamePolicy.MinLength || *username.MaxLength != DefaultUsernamePolicy.MaxLength {
+		t.Errorf("Username length = %d..%d; want the policy's %d..%d",
+			*username.MinLength, *username.MaxLength, DefaultUsernamePolicy.MinLength, DefaultUsernamePolicy.MaxLength)
+	}
+	if got := username.ErrorMessage["maxLength"]; got != "Username must be at most 20 characters long" {
+		t.Errorf("Username maxLength message = %q", got)
+	}
+
+	age := s.Properties["Age"]
+	if age.Type != "integer" || *age.Minimum != 0 || *age.Maximum != 150 {
+		t.Errorf("Age = {%s %v %v}; want integer 0..150", age.Type, *age.Minimum, *age.Maximum)
+	}
+	if got := age.ErrorMessage["maximum"]; got != "Age must be between 0 and 150" {
+		t.Errorf("Age maximum message = %q", got)
+	}
+
+	email := s.Properties["Email"]
+	if email.Format != "idn-email" || *email.MaxLength != 254 {
+		t.Errorf("Email = {%s %d}; want idn-email up to 254", email.Format, *email.MaxLength)
+	}
+
+	password := s.Properties["Password"]
+	if !password.WriteOnly || *password.MinLength != DefaultPasswordPolicy.MinLength {
+		t.Errorf("Password = {writeOnly %v minLength %d}; want write-only with minLength %d",
+			password.WriteOnly, *password.MinLength, DefaultPasswordPolicy.MinLength)
+	}
+
+	phone := s.Properties["Phone"]
+	if !regexp.MustCompile(phone.Pattern).MatchString("+44 (0)20 7946-0958") || regexp.MustCompile(phone.Pattern).MatchString("call me") {
+		t.Errorf("Phone pattern = %q; want digits and separators only", phone.Pattern)
+	}
+
+	if dob := s.Properties["DateOfBirth"]; dob.Type != "string" || dob.Format != "date-time" {
+		t.Errorf("DateOfBirth = {%s %s}; want string date-time", dob.Type, dob.Format)
+	}
+}
+
+// The tags on User must describe the same rules as ValidateUser, or the
+// schema would drift from the server.
+func TestUserTagsMatchValidateUser(t *testing.T) {
+	users := []User{
+		{Username: "johndoe", Email: "john@example.com", Age: 30},
+		{Username: "", Email: "john@example.com", Age: 30},
+		{Username: "jo", Email: "john@example.com", Age: 30},
+		{Username: strings.Repeat("j", 21), Email: "john@example.com", Age: 30},
+		{Username: "john..doe", Email: "john@example.com", Age: 30},
+		{Username: "johndoe", Email: "", Age: 30},
+		{Username: "johndoe", Email: "john@", Age: 30},
+		{Username: "johndoe", Email: "john@example.com", Age: -1},
+		{Username: "johndoe", Email: "john@example.com", Age: 151},
+		{Username: "johndoe", Email: "john@example.com", Age: 30, Password: "short"},
+		{Username: "johndoe", Email: "john@example.com", Age: 30, Password: "violet tractor sings"},
+		{Username: "johndoe", Email: "john@example.com", Age: 30, Phone: "+44 20 7946 0958"},
+		{Username: "johndoe", Email: "john@example.com", Age: 30, Phone: "020 7946 0958"},
+		{Username: "johndoe", Email: "john@example.com", Age: 30, Phone: "+999 1234"},
+	}
+
+	for _, u := range users {
+		byValidator := ValidateUser(u) == nil
+		byTags := ValidateStruct(u) == nil
+		if byValidator != byTags {
+			t.Errorf("%+v: ValidateUser valid = %v, ValidateStruct valid = %v", u, byValidator, byTags)
+		}
+	}
+
+	for _, age := range []int{-1, 151} {
+		u := User{Username: "johndoe", Email: "john@example.com", Age: age}
+		if got := ErrorCode(ValidateStruct(u)); got != "age.invalid" {
+			t.Errorf("ValidateStruct(Age: %d) code = %s; want age.invalid", age, got)
+		}
+	}
+}
+
+func TestJSONSchemaNestedAndCollections(t *testing.T) {
+	s, err := JSONSchema(tagCustomer{})
+	if err != nil {
+		t.Fatalf("JSONSchema error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		got  any
+		want any
+	}{
+		{"Home ref", s.Properties["Home"].Ref, "#/$defs/tagAddress"},
+		{"Addresses items", s.Properties["Addresses"].Items.Ref, "#/$defs/tagAddress"},
+		{"Addresses maxItems", *s.Properties["Addresses"].MaxItems, 3},
+		{"Addresses message", s.Properties["Addresses"].ErrorMessage["maxItems"], "must have at most 3 items"},
+		{"Labeled values", s.Properties["Labeled"].AdditionalProperties.Ref, "#/$defs/tagAddress"},
+		{"Plan enum", s.Properties["Plan"].Enum, []any{"free", "pro"}},
+		{"Plan message", s.Properties["Plan"].ErrorMessage["enum"], "must be one of: free, pro"},
+		{"Nickname pointer", s.Properties["Nickname"].Type, "string"},
+		{"Zip length", [2]int{*s.Defs["tagAddress"].Properties["Zip"].MinLength, *s.Defs["tagAddress"].Properties["Zip"].MaxLength}, [2]int{5, 5}},
+		{"nested required", s.Defs["tagAddress"].Required, []string{"Street"}},
+		{"required message", s.ErrorMessage["required"], map[string]string{"Name": "is required", "Email": "is required"}},
+	}
+	for _, tt := range tests {
+		if !reflect.DeepEqual(tt.got, tt.want) {
+			t.Errorf("%s = %v; want %v", tt.name, tt.got, tt.want)
+		}
+	}
+	for _, name := range []string{"Ignored", "internal"} {
+		if _, ok := s.Properties[name]; ok {
+			t.Errorf("schema has property %q; want it skipped", name)
+		}
+	}
+}
+
+type schemaNode struct {
+	Label    string        `json:"label" validate:"required,max=10"`
+	Children []*schemaNode `json:"children,omitempty" validate:"max=4"`
+	Parent   *schemaNode   `json:"-"`
+	Rank     float64       `json:"rank" validate:"oneof=1 2.5"`
+	Code     string        `json:"code" validate:"shout"`
+}
+
+func TestJSONSchemaRecursionTagsAndCustomRules(t *testing.T) {
+	sv := NewStructValidator()
+	sv.RegisterRule("shout", func(reflect.Value, string) error { return nil })
+
+	s, err := sv.JSONSchema(schemaNode{}, SchemaOptions{ID: "https://example.com/node.json"})
+	if err != nil {
+		t.Fatalf("JSONSchema error = %v", err)
+	}
+	if s.ID != "https://example.com/node.json" {
+		t.Errorf("ID = %q", s.ID)
+	}
+	if got := s.Properties["children"].Items.Ref; got != "#" {
+		t.Errorf("children items ref = %q; want #", got)
+	}
+	if _, ok := s.Properties["Parent"]; ok {
+		t.Error(`json:"-" field was included`)
+	}
+	if got := s.Properties["rank"].Enum; !reflect.DeepEqual(got, []any{1.0, 2.5}) {
+		t.Errorf("rank enum = %v; want numbers", got)
+	}
+	if got := s.Properties["code"]; got.Pattern != "" || got.ErrorMessage != nil {
+		t.Errorf("undescribed custom rule produced %+v", got)
+	}
+
+	sv.RegisterRuleSchema("shout", func(s *Schema, _ reflect.Type, _ string) {
+		s.Pattern = "^[A-Z]+$"
+		s.SetErrorCode("pattern", "code.shout", nil)
+	})
+	s, err = sv.JSONSchema(schemaNode{}, SchemaOptions{})
+	if err != nil {
+		t.Fatalf("JSONSchema error = %v", err)
+	}
+	if got := s.Properties["code"]; got.Pattern != "^[A-Z]+$" || got.ErrorMessage["pattern"] != "code.shout" {
+		t.Errorf("custom rule schema = {%q %v}", got.Pattern, got.ErrorMessage)
+	}
+}
+
+func TestJSONSchemaTranslatedMessages(t *testing.T) {
+	c := NewCatalog("en")
+	c.Add("es", map[string]string{
+		"required":           "es obligatorio",
+		"username.too_short": "El nombre debe tener al menos {min} caracteres",
+	})
+
+	s, err := defaultStructValidator.JSONSchema(User{}, SchemaOptions{Translator: c, Locale: "es"})
+	if err != nil {
+		t.Fatalf("JSONSchema error = %v", err)
+	}
+	if got := s.Properties["Username"].ErrorMessage["minLength"]; got != "El nombre debe tener al menos 3 caracteres" {
+		t.Errorf("minLength message = %q", got)
+	}
+	if got := s.ErrorMessage["required"].(map[string]string)["Email"]; got != "es obligatorio" {
+		t.Errorf("required message = %q", got)
+	}
+}
+
+func TestUsernamePolicySchemaPattern(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy UsernamePolicy
+		want   string
+	}{
+		{"default", DefaultUsernamePolicy, `^(?=\p{L})(?:[._\-]?[\p{L}\p{N}])*[._\-]?$`},
+		{"no separators", UsernamePolicy{}, `^[\p{L}\p{N}]*$`},
+		{"consecutive allowed", UsernamePolicy{Separators: "_"}, `^[\p{L}\p{N}_]*$`},
+		{"ascii", UsernamePolicy{AllowedChars: ASCIILetterOrDigit, AllowedCharsClass: "A-Za-z0-9", Separators: "-", NoConsecutiveSeparators: true},
+			`^(?:[\-]?[A-Za-z0-9])*[\-]?$`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Schema{}
+			tt.policy.ApplySchema(s)
+			if s.Pattern != tt.want {
+				t.Errorf("Pattern = %q; want %q", s.Pattern, tt.want)
+			}
+		})
+	}
+
+	s := &Schema{}
+	UsernamePolicy{AllowedChars: ASCIILetterOrDigit}.ApplySchema(s)
+	if s.Pattern != "" {
+		t.Errorf("Pattern = %q; want none for AllowedChars without a class", s.Pattern)
+	}
+
+	// Go's regexp has no lookahead, so check the rest of the default
+	// pattern against the policy.
+	s = &Schema{}
+	DefaultUsernamePolicy.ApplySchema(s)
+	re := regexp.MustCompile(strings.Replace(s.Pattern, `(?=\p{L})`, "", 1))
+	for _, name := range []string{"john.doe", "jöhn_doe", "john..doe", "john doe", "john.", "john-_doe"} {
+		want := DefaultUsernamePolicy.Validate(name) == nil
+		if got := re.MatchString(name); got != want {
+			t.Errorf("pattern matches %q = %v; policy accepts it = %v", name, got, want)
+		}
+	}
+}
+
+func TestPasswordPolicySchema(t *testing.T) {
+	s := &Schema{}
+	PasswordPolicy{MinLength: 12, RequireUpper: true, RequireDigit: true}.ApplySchema(s)
+	if s.Pattern != `^(?=.*\p{Lu})(?=.*\p{Nd})` || !s.WriteOnly || *s.MinLength != 12 || s.MaxLength != nil {
+		t.Errorf("schema = {%q %v %v %v}", s.Pattern, s.WriteOnly, s.MinLength, s.MaxLength)
+	}
+}
+
+func TestJSONSchemaErrors(t *testing.T) {
+	if _, err := JSONSchema(42); !errors.Is(err, ErrInvalidTag) {
+		t.Errorf("JSONSchema(42) error = %v; want ErrInvalidTag", err)
+	}
+	type bad struct {
+		Name string `validate:"nope"`
+	}
+	if _, err := JSONSchema(bad{}); !errors.Is(err, ErrInvalidTag) {
+		t.Errorf("JSONSchema(bad) error = %v; want ErrInvalidTag", err)
+	}
+
+	s, _ := JSONSchema(User{})
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("Marshal error = %v", err)
+	}
+	for _, key := range []string{`"$schema"`, `"errorMessage"`, `"minLength":3`} {
+		if !strings.Contains(string(data), key) {
+			t.Errorf("JSON %s does not contain %s", data, key)
+		}
+	}
+}
+
+func TestJSONSchemaUsesCurrentPolicies(t *testing.T) {
+	username := DefaultUsernamePolicy
+	t.Cleanup(func() { DefaultUsernamePolicy = username })
+	DefaultUsernamePolicy.MaxLength = 8
+
+	s, err := JSONSchema(User{})
+	if err != nil {
+		t.Fatalf("JSONSchema error = %v", err)
+	}
+	if got := *s.Properties["Username"].MaxLength; got != 8 {
+		t.Errorf("Username maxLength = %d; want 8", got)
+	}
+}
+
+type schemaOptional struct {
+	Name     string            `validate:"max=10"`
+	Age      int               `validate:"max=130"`
+	Tags     []string          `validate:"max=3"`
+	Labels   map[string]string `validate:"max=2"`
+	Nickname *string           `validate:"min=2"`
+	Plan     *string           `validate:"oneof=free pro"`
+	Home     *tagAddress
+	Children []*schemaOptional
+	Scores   [2]int
+	Created  time.Time
+	Extra    any
+}
+
+func TestJSONSchemaAcceptsMarshaledZeroValues(t *testing.T) {
+	root := marshaledSchema(t, schemaOptional{})
+	for name, v := range map[string]any{
+		"zero":      schemaOptional{},
+		"nil items": schemaOptional{Children: []*schemaOptional{nil, {}}, Labels: map[string]string{}},
+	} {
+		if err := checkJSONSchema(root, root, marshaledValue(t, v)); err != nil {
+			t.Errorf("%s: marshaled value does not match its schema: %v", name, err)
+		}
+	}
+
+	type owned struct {
+		Owner *tagAddress `validate:"required"`
+		Tags  []string    `validate:"required"`
+	}
+	root = marshaledSchema(t, owned{})
+	if err := checkJSONSchema(root, root, marshaledValue(t, owned{})); err == nil {
+		t.Error("null matched the schema of required fields")
+	}
+	props := root["properties"].(map[string]any)
+	if got := props["Owner"].(map[string]any)["$ref"]; got != "#/$defs/tagAddress" {
+		t.Errorf("Owner = %v; want a plain $ref", props["Owner"])
+	}
+	if got := props["Tags"].(map[string]any)["type"]; got != "array" {
+		t.Errorf("Tags type = %v; want array", got)
+	}
+}
+
+func marshaledSchema(t *testing.T, v any) map[string]any {
+	t.Helper()
+	s, err := JSONSchema(v)
+	if err != nil {
+		t.Fatalf("JSONSchema error = %v", err)
+	}
+	return marshaledValue(t, s).(map[string]any)
+}
+
+func marshaledValue(t *testing.T, v any) any {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("Marshal error = %v", err)
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("Unmarshal error = %v", err)
+	}
+	return out
+}
+
+// checkJSONSchema checks a decoded JSON value against the keywords the
+// generator produces.
+func checkJSONSchema(root, s map[string]any, v any) error {
+	if ref, ok := s["$ref"].(string); ok {
+		target := root
+		if ref != "#" {
+			target = root["$defs"].(map[string]any)[strings.TrimPrefix(ref, "#/$defs/")].(map[string]any)
+		}
+		if err := checkJSONSchema(root, target, v); err != nil {
+			return err
+		}
+	}
+	if anyOf, ok := s["anyOf"].([]any); ok {
+		if !slices.ContainsFunc(anyOf, func(sub any) bool { return checkJSONSchema(root, sub.(map[string]any), v) == nil }) {
+			return fmt.Errorf("%v matches no anyOf branch", v)
+		}
+	}
+	if typ, ok := s["type"]; ok {
+		types, ok := typ.([]any)
+		if !ok {
+			types = []any{typ}
+		}
+		kind := jsonType(v)
+		if n, ok := v.(float64); ok && n == math.Trunc(n) && slices.Contains(types, any("integer")) {
+			kind = "integer"
+		}
+		if !slices.Contains(types, any(kind)) {
+			return fmt.Errorf("%v is not of type %v", v, typ)
+		}
+	}
+	if enum, ok := s["enum"].([]any); ok && !slices.Contains(enum, v) {
+		return fmt.Errorf("%v is not in %v", v, enum)
+	}
+
+	size := -1.0
+	switch v := v.(type) {
+	case map[string]any:
+		size = float64(len(v))
+		required, _ := s["required"].([]any)
+		for _, name := range required {
+			if _, ok := v[name.(string)]; !ok {
+				return fmt.Errorf("missing %v", name)
+			}
+		}
+		props, _ := s["properties"].(map[string]any)
+		for key, value := range v {
+			sub, ok := props[key].(map[string]any)
+			if !ok {
+				sub, ok = s["additionalProperties"].(map[string]any)
+			}
+			if ok {
+				if err := checkJSONSchema(root, sub, value); err != nil {
+					return fmt.Errorf("%s: %w", key, err)
+				}
+			}
+		}
+	case []any:
+		size = float64(len(v))
+		for i, item := range v {
+			if items, ok := s["items"].(map[string]any); ok {
+				if err := checkJSONSchema(root, items, item); err != nil {
+					return fmt.Errorf("[%d]: %w", i, err)
+				}
+			}
+		}
+	case string:
+		size = float64(utf8.RuneCountInString(v))
+	case float64:
+		size = v
+	}
+	for _, bound := range []struct {
+		keyword string
+		kind    string
+		lower   bool
+	}{
+		{"minLength", "string", true}, {"maxLength", "string", false},
+		{"minItems", "array", true}, {"maxItems", "array", false},
+		{"minProperties", "object", true}, {"maxProperties", "object", false},
+		{"minimum", "number", true}, {"maximum", "number", false},
+	} {
+		limit, ok := s[bound.keyword].(float64)
+		if ok && jsonType(v) == bound.kind && (bound.lower && size < limit || !bound.lower && size > limit) {
+			return fmt.Errorf("%v fails %s %v", v, bound.keyword, limit)
+		}
+	}
+	return nil
+}
+
+func jsonType(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	}
+	return "object"
+}
@@ -0,0 +1,500 @@
+package basics
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+var (
+	ErrRequired   = errors.New("value is required")
+	ErrTooShort   = errors.New("value is too short")
+	ErrTooLong    = errors.New("value is too long")
+	ErrTooSmall   = errors.New("value is too small")
+	ErrTooLarge   = errors.New("value is too large")
+	ErrNotOneOf   = errors.New("value is not one of the allowed values")
+	ErrInvalidTag = errors.New("invalid validate tag")
+
+	// ErrTooFewItems and ErrTooManyItems are reported for collections
+	// instead of ErrTooShort and ErrTooLong, which they wrap.
+	ErrTooFewItems  = fmt.Errorf("%w: too few items", ErrTooShort)
+	ErrTooManyItems = fmt.Errorf("%w: too many items", ErrTooLong)
+)
+
+// TagRuleFunc implements a rule used in `validate` struct tags. It receives
+// the field's value, with pointers already dereferenced, and the text after
+// "=" in the tag ("" if there is none). Nil pointers skip every rule except
+// "required".
+type TagRuleFunc func(value reflect.Value, param string) error
+
+// StructValidator validates structs using `validate:"..."` field tags such
+// as `validate:"required,min=3,max=20,email"`. Rules within a tag run in
+// order and stop at the first failure. Nested structs, and the elements of
+// slices, arrays and maps, are validated recursively, with error paths
+// like "Addresses[2].Zip".
+//
+// The special rule "omitempty" skips the remaining rules when the field
+// holds its zero value, and a tag of "-" skips the field entirely.
+type StructValidator struct {
+	mu      sync.RWMutex
+	rules   map[string]TagRuleFunc
+	schemas map[string]TagSchemaFunc
+	// cache holds parsed tags. It is filled and cleared under mu, so an
+	// entry cannot outlive the rules it was built from.
+	cache map[reflect.Type]*structInfo
+}
+
+type structInfo struct {
+	fields []fieldInfo
+}
+
+type fieldInfo struct {
+	index     int
+	name      string
+	omitEmpty bool
+	// sensitive fields, such as passwords, are left out of errors.
+	sensitive bool
+	rules     []tagRule
+}
+
+type tagRule struct {
+	name  string
+	param string
+	fn    TagRuleFunc
+}
+
+// NewStructValidator returns a validator with the built-in rules: required,
+// min, max, len, oneof, email, username, password, phone and age. The
+// policy rules and schemas use the Default*Policy variables and AgeRules as
+// they are when a value is validated or a schema generated, so changes to
+// them apply to ValidateStruct and JSONSchema too. Values of fields tagged
+// "password" are never copied into errors. The phone rule takes an optional
+// default region, as in "phone=GB".
+func NewStructValidator() *StructValidator {
+	return &StructValidator{
+		cache: make(map[reflect.Type]*structInfo),
+		rules: map[string]TagRuleFunc{
+			"required": ruleRequired,
+			"min":      ruleMin,
+			"max":      ruleMax,
+			"len":      ruleLen,
+			"oneof":    ruleOneOf,
+			"email":    stringRule(func(s string) error { return DefaultEmailPolicy.Validate(s) }),
+			"username": stringRule(func(s string) error { return DefaultUsernamePolicy.Validate(s) }),
+			"password": stringRule(func(s string) error { return DefaultPasswordPolicy.Validate(s) }),
+			"phone":    rulePhone,
+			"age":      ruleAge,
+		},
+		schemas: map[string]TagSchemaFunc{
+			"min":      schemaMin,
+			"max":      schemaMax,
+			"len":      schemaLen,
+			"oneof":    schemaOneOf,
+			"email":    schemaPolicy(func(s *Schema) { DefaultEmailPolicy.ApplySchema(s) }),
+			"username": schemaPolicy(func(s *Schema) { DefaultUsernamePolicy.ApplySchema(s) }),
+			"password": schemaPolicy(func(s *Schema) { DefaultPasswordPolicy.ApplySchema(s) }),
+			"phone":    schemaPolicy(func(s *Schema) { DefaultPhonePolicy.ApplySchema(s) }),
+			"age":      schemaAge,
+		},
+	}
+}
+
+// RegisterRule adds or replaces a tag rule.
+func (sv *StructValidator) RegisterRule(name string, fn TagRuleFunc) {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	sv.rules[name] = fn
+	// Parsed tags hold resolved rule functions.
+	clear(sv.cache)
+}
+
+// RegisterRuleSchema describes a tag rule in generated JSON Schemas.
+func (sv *StructValidator) RegisterRuleSchema(name string, fn TagSchemaFunc) {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	sv.schemas[name] = fn
+}
+
+var defaultStructValidator = NewStructValidator()
+
+// RegisterRule adds a tag rule to the validator used by ValidateStruct.
+func RegisterRule(name string, fn TagRuleFunc) {
+	defaultStructValidator.RegisterRule(name, fn)
+}
+
+// RegisterRuleSchema describes a tag rule for JSONSchema.
+func RegisterRuleSchema(name string, fn TagSchemaFunc) {
+	defaultStructValidator.RegisterRuleSchema(name, fn)
+}
+
+// ValidateStruct validates v, a struct or pointer to struct, using its
+// `validate` tags.
+func ValidateStruct(v any) error {
+	return defaultStructValidator.Validate(v)
+}
+
+// Validate returns nil or a ValidationErrors listing every failing field.
+// A malformed tag is reported as an error wrapping ErrInvalidTag.
+func (sv *StructValidator) Validate(v any) error {
+	value := reflect.ValueOf(v)
+	seen := make(map[visit]bool)
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return fmt.Errorf("%w: nil pointer", ErrInvalidTag)
+		}
+		seen[visitOf(value)] = true
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return fmt.Errorf("%w: %T is not a struct", ErrInvalidTag, v)
+	}
+
+	var errs ValidationErrors
+	if err := sv.validateStruct("", value, &errs, seen); err != nil {
+		return err
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func (sv *StructValidator) validateStruct(path string, value reflect.Value, errs *ValidationErrors, seen map[visit]bool) error {
+	info, err := sv.structInfo(value.Type())
+	if err != nil {
+		return err
+	}
+
+	for _, field := range info.fields {
+		fieldPath := field.name
+		if path != "" {
+			fieldPath = path + "." + field.name
+		}
+		fv := value.Field(field.index)
+
+		if !field.omitEmpty || !fv.IsZero() {
+			if err := applyTagRules(fv, field.rules); err != nil {
+				var value any
+				if !field.sensitive {
+					value = fv.Interface()
+				}
+				*errs = append(*errs, NewFieldError(fieldPath, value, err))
+				continue
+			}
+		}
+		if err := sv.descend(fieldPath, fv, errs, seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// visit identifies a pointer, map or slice being descended into. The type
+// tells apart a struct and its first field, which share an address.
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+	len int
+}
+
+func visitOf(value reflect.Value) visit {
+	v := visit{ptr: value.Pointer(), typ: value.Type()}
+	if value.Kind() == reflect.Slice {
+		v.len = value.Len()
+	}
+	return v
+}
+
+// descend validates the structs reachable from value. seen holds the
+// pointers, maps and slices on the current path, so that a cyclic graph
+// is walked around once rather than forever. Values shared by several
+// fields are still validated under each path.
+func (sv *StructValidator) descend(path string, value reflect.Value, errs *ValidationErrors, seen map[visit]bool) error {
+	enter := func(value reflect.Value) bool {
+		v := visitOf(value)
+		if seen[v] {
+			return false
+		}
+		seen[v] = true
+		return true
+	}
+
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil
+		}
+		if value.Kind() == reflect.Pointer {
+			if !enter(value) {
+				return nil
+			}
+			defer delete(seen, visitOf(value))
+		}
+		value = value.Elem()
+	}
+	if k := value.Kind(); (k == reflect.Map || k == reflect.Slice) && !value.IsNil() {
+		if !enter(value) {
+			return nil
+		}
+		defer delete(seen, visitOf(value))
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		return sv.validateStruct(path, value, errs, seen)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if err := sv.descend(fmt.Sprintf("%s[%d]", path, i), value.Index(i), errs, seen); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		keys := value.MapKeys()
+		// Sort so that errors come out in a stable order.
+		slices.SortFunc(keys, func(a, b reflect.Value) int {
+			return cmp.Compare(fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface()))
+		})
+		for _, key := range keys {
+			if err := sv.descend(fmt.Sprintf("%s[%v]", path, key.Interface()), value.MapIndex(key), errs, seen); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func applyTagRules(value reflect.Value, rules []tagRule) error {
+	for _, rule := range rules {
+		v := value
+		if rule.name != "required" {
+			for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+				if v.IsNil() {
+					// Only "required" has an opinion about nil values.
+					return nil
+				}
+				v = v.Elem()
+			}
+		}
+		if err := rule.fn(v, rule.param); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (sv *StructValidator) structInfo(t reflect.Type) (*structInfo, error) {
+	sv.mu.RLock()
+	cached, ok := sv.cache[t]
+	sv.mu.RUnlock()
+	if ok {
+		return cached, nil
+	}
+
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	if cached, ok := sv.cache[t]; ok {
+		return cached, nil
+	}
+
+	info := &structInfo{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("validate")
+		if !f.IsExported() || tag == "-" {
+			continue
+		}
+
+		field := fieldInfo{index: i, name: f.Name}
+		for _, part := range strings.Split(tag, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			if part == "omitempty" {
+				field.omitEmpty = true
+				continue
+			}
+
+			name, param, _ := strings.Cut(part, "=")
+			fn, ok := sv.rules[name]
+			if !ok {
+				return nil, fmt.Errorf("%w: %s.%s: unknown rule %q", ErrInvalidTag, t.Name(), f.Name, name)
+			}
+			if err := checkTagParam(name, param, f.Type); err != nil {
+				return nil, fmt.Errorf("%w: %s.%s: %v", ErrInvalidTag, t.Name(), f.Name, err)
+			}
+			field.rules = append(field.rules, tagRule{name: name, param: param, fn: fn})
+			field.sensitive = field.sensitive || name == "password"
+		}
+		info.fields = append(info.fields, field)
+	}
+
+	sv.cache[t] = info
+	return info, nil
+}
+
+// checkTagParam catches misuse of built-in rules when a type is first seen
+// rather than when a value happens to reach them.
+func checkTagParam(name, param string, t reflect.Type) error {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch name {
+	case "min", "max", "len":
+		if _, err := strconv.ParseFloat(param, 64); err != nil {
+			return fmt.Errorf("%s needs a numeric parameter, got %q", name, param)
+		}
+		if _, _, ok := measure(reflect.Zero(t)); !ok && t.Kind() != reflect.Interface {
+			return fmt.Errorf("%s cannot be used on %s", name, t)
+		}
+	case "email", "username", "password", "phone":
+		if t.Kind() != reflect.String {
+			return fmt.Errorf("%s can only be used on strings, not %s", name, t)
+		}
+		if _, ok := DefaultPhoneRegions.Region(param); name == "phone" && param != "" && !ok {
+			return fmt.Errorf("phone has an unknown region %q", param)
+		}
+	case "age":
+		if !reflect.Zero(t).CanInt() {
+			return fmt.Errorf("age can only be used on integers, not %s", t)
+		}
+	case "oneof":
+		if strings.TrimSpace(param) == "" {
+			return errors.New("oneof needs at least one value")
+		}
+	}
+	return nil
+}
+
+func ruleRequired(value reflect.Value, _ string) error {
+	if !value.IsValid() || value.IsZero() {
+		return ErrRequired
+	}
+	if value.Kind() == reflect.String && strings.TrimSpace(value.String()) == "" {
+		return ErrRequired
+	}
+	return nil
+}
+
+func ruleMin(value reflect.Value, param string) error {
+	limit, _ := strconv.ParseFloat(param, 64)
+	n, isSize, ok := measure(value)
+	if !ok {
+		return fmt.Errorf("%w: min cannot be used on %s", ErrInvalidTag, value.Kind())
+	}
+	if n >= limit {
+		return nil
+	}
+	sentinel := ErrTooSmall
+	if isSize {
+		sentinel = ErrTooShort
+		if value.Kind() != reflect.String {
+			sentinel = ErrTooFewItems
+		}
+	}
+	return &ParamError{
+		Err:     sentinel,
+		Params:  map[string]any{"min": param},
+		Message: fmt.Sprintf("%v (minimum %s)", sentinel, param),
+	}
+}
+
+func ruleMax(value reflect.Value, param string) error {
+	limit, _ := strconv.ParseFloat(param, 64)
+	n, isSize, ok := measure(value)
+	if !ok {
+		return fmt.Errorf("%w: max cannot be used on %s", ErrInvalidTag, value.Kind())
+	}
+	if n <= limit {
+		return nil
+	}
+	sentinel := ErrTooLarge
+	if isSize {
+		sentinel = ErrTooLong
+		if value.Kind() != reflect.String {
+			sentinel = ErrTooManyItems
+		}
+	}
+	return &ParamError{
+		Err:     sentinel,
+		Params:  map[string]any{"max": param},
+		Message: fmt.Sprintf("%v (maximum %s)", sentinel, param),
+	}
+}
+
+func ruleLen(value reflect.Value, param string) error {
+	if err := ruleMin(value, param); err != nil {
+		return err
+	}
+	return ruleMax(value, param)
+}
+
+// measure returns the number min and max compare against: the character
+// count of a string, the length of a collection, or a number's value. ok
+// is false for kinds that have no such measure.
+func measure(value reflect.Value) (n float64, isSize, ok bool) {
+	switch value.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(value.String())), true, true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(value.Len()), true, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), false, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(value.Uint()), false, true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), false, true
+	}
+	return 0, false, false
+}
+
+// ruleOneOf accepts values whose string form is one of the space-separated
+// words in param, e.g. `validate:"oneof=red green blue"`.
+func ruleOneOf(value reflect.Value, param string) error {
+	s := fmt.Sprint(value.Interface())
+	if slices.Contains(strings.Fields(param), s) {
+		return nil
+	}
+	return &ParamError{
+		Err:     ErrNotOneOf,
+		Params:  map[string]any{"value": s, "allowed": strings.Join(strings.Fields(param), ", ")},
+		Message: fmt.Sprintf("%v: %q (allowed: %s)", ErrNotOneOf, s, param),
+	}
+}
+
+// stringRule adapts a string validator, such as a policy's Validate
+// method, to a tag rule. Empty strings are left to "required".
+func stringRule(validate func(string) error) TagRuleFunc {
+	return func(value reflect.Value, _ string) error {
+		if value.Kind() != reflect.String {
+			return fmt.Errorf("%w: expected a string, got %s", ErrInvalidTag, value.Kind())
+		}
+		if value.String() == "" {
+			return nil
+		}
+		return validate(value.String())
+	}
+}
+
+// rulePhone validates a phone number, reading numbers without a country
+// code as numbers of the region given as the parameter, if any.
+func rulePhone(value reflect.Value, param string) error {
+	policy := DefaultPhonePolicy
+	if param != "" {
+		policy.DefaultRegion = param
+	}
+	return stringRule(policy.Validate)(value, param)
+}
+
+// ruleAge validates an age with AgeRules.
+func ruleAge(value reflect.Value, _ string) error {
+	if !value.CanInt() {
+		return fmt.Errorf("%w: expected an integer, got %s", ErrInvalidTag, value.Kind())
+	}
+	return AgeRules.Validate(int(value.Int()))
+}
@@ -0,0 +1,281 @@
+package basics
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type tagAddress struct {
+	Street string `validate:"required"`
+	Zip    string `validate:"len=5"`
+}
+
+type tagCustomer struct {
+	Name      string       `validate:"required,min=3,max=20"`
+	Email     string       `validate:"required,email"`
+	Age       int          `validate:"min=18,max=130"`
+	Plan      string       `validate:"oneof=free pro"`
+	Nickname  *string      `validate:"min=2"`
+	Website   string       `validate:"omitempty,min=8"`
+	Home      tagAddress   // nested without a tag
+	Addresses []tagAddress `validate:"max=3"`
+	Labeled   map[string]tagAddress
+	Ignored   string `validate:"-"`
+	internal  string
+}
+
+func validCustomer() tagCustomer {
+	return tagCustomer{
+		Name:      "Ann",
+		Email:     "ann@example.com",
+		Age:       30,
+		Plan:      "pro",
+		Home:      tagAddress{Street: "Main St", Zip: "12345"},
+		Addresses: []tagAddress{{Street: "Elm St", Zip: "54321"}},
+	}
+}
+
+func TestValidateStructValid(t *testing.T) {
+	c := validCustomer()
+	if err := ValidateStruct(c); err != nil {
+		t.Errorf("ValidateStruct(value) returned error: %v", err)
+	}
+	if err := ValidateStruct(&c); err != nil {
+		t.Errorf("ValidateStruct(pointer) returned error: %v", err)
+	}
+}
+
+func TestValidateStructErrors(t *testing.T) {
+	short := "x"
+	c := validCustomer()
+	c.Name = "  "
+	c.Email = "not-an-email"
+	c.Age = 12
+	c.Plan = "gold"
+	c.Nickname = &short
+	c.Website = "a.io"
+	c.Home.Zip = "123"
+	c.Addresses = append(c.Addresses, tagAddress{Zip: "12345"}, tagAddress{Street: "Oak", Zip: "123456"})
+	c.Labeled = map[string]tagAddress{"work": {Street: "Pine", Zip: "1"}}
+	c.Ignored = ""
+
+	err := ValidateStruct(c)
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("error = %v (%T); want ValidationErrors", err, err)
+	}
+
+	want := []struct {
+		path string
+		code string
+		err  error
+	}{
+		{"Name", "required", ErrRequired},
+		{"Email", "email.missing_at", ErrInvalidEmail},
+		{"Age", "too_small", ErrTooSmall},
+		{"Plan", "not_one_of", ErrNotOneOf},
+		{"Nickname", "too_short", ErrTooShort},
+		{"Website", "too_short", ErrTooShort},
+		{"Home.Zip", "too_short", ErrTooShort},
+		{"Addresses[1].Street", "required", ErrRequired},
+		{"Addresses[2].Zip", "too_long", ErrTooLong},
+		{"Labeled[work].Zip", "too_short", ErrTooShort},
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("got %d errors (%v); want %d", len(errs), errs, len(want))
+	}
+	for i, w := range want {
+		if errs[i].Path != w.path || errs[i].Code != w.code || !errors.Is(errs[i], w.err) {
+			t.Errorf("errs[%d] = {%s %s %v}; want {%s %s %v}", i, errs[i].Path, errs[i].Code, errs[i].Err, w.path, w.code, w.err)
+		}
+	}
+}
+
+func TestValidateStructOmitEmptyAndNil(t *testing.T) {
+	c := validCustomer()
+	c.Website = ""
+	c.Nickname = nil
+	if err := ValidateStruct(c); err != nil {
+		t.Errorf("ValidateStruct returned error: %v", err)
+	}
+
+	type withPointer struct {
+		Ref *tagAddress `validate:"required"`
+	}
+	err := ValidateStruct(withPointer{})
+	if !errors.Is(err, ErrRequired) {
+		t.Errorf("nil required pointer: error = %v; want ErrRequired", err)
+	}
+
+	err = ValidateStruct(withPointer{Ref: &tagAddress{Street: "Main"}})
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Path != "Ref.Zip" {
+		t.Errorf("pointer to nested struct: error = %v; want Ref.Zip", err)
+	}
+}
+
+func TestValidateStructCustomRule(t *testing.T) {
+	errNotUpper := errors.New("value must be upper case")
+	RegisterErrorCode(errNotUpper, "upper")
+
+	sv := NewStructValidator()
+	sv.RegisterRule("upper", func(value reflect.Value, _ string) error {
+		if s := value.String(); s != strings.ToUpper(s) {
+			return errNotUpper
+		}
+		return nil
+	})
+
+	type country struct {
+		Code string `validate:"required,len=2,upper"`
+	}
+	err := sv.Validate(country{Code: "de"})
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Code != "upper" || errs[0].Path != "Code" {
+		t.Fatalf("error = %v; want a single upper error on Code", err)
+	}
+	if err := sv.Validate(country{Code: "DE"}); err != nil {
+		t.Errorf("Validate(DE) returned error: %v", err)
+	}
+
+	// The rule is local to sv.
+	if err := ValidateStruct(country{Code: "DE"}); !errors.Is(err, ErrInvalidTag) {
+		t.Errorf("ValidateStruct with unregistered rule: error = %v; want ErrInvalidTag", err)
+	}
+}
+
+func TestValidateStructInvalidTags(t *testing.T) {
+	type unknownRule struct {
+		Name string `validate:"nope"`
+	}
+	type badParam struct {
+		Name string `validate:"min=three"`
+	}
+	type wrongKind struct {
+		Active bool `validate:"max=1"`
+	}
+	type emailOnInt struct {
+		Count int `validate:"email"`
+	}
+
+	for _, v := range []any{unknownRule{}, badParam{}, wrongKind{}, emailOnInt{}, 42, (*tagAddress)(nil)} {
+		if err := ValidateStruct(v); !errors.Is(err, ErrInvalidTag) {
+			t.Errorf("ValidateStruct(%T) error = %v; want ErrInvalidTag", v, err)
+		}
+	}
+}
+
+func TestValidateStructCachesTypes(t *testing.T) {
+	sv := NewStructValidator()
+	if err := sv.Validate(tagAddress{Street: "Main", Zip: "12345"}); err != nil {
+		t.Fatalf("Validate returned error: %v", err)
+	}
+	if _, ok := sv.cache[reflect.TypeOf(tagAddress{})]; !ok {
+		t.Error("parsed tags were not cached")
+	}
+
+	sv.RegisterRule("noop", func(reflect.Value, string) error { return nil })
+	if _, ok := sv.cache[reflect.TypeOf(tagAddress{})]; ok {
+		t.Error("RegisterRule did not clear the cache")
+	}
+}
+
+func TestValidateStructUsesCurrentPolicies(t *testing.T) {
+	username, email := DefaultUsernamePolicy, DefaultEmailPolicy
+	t.Cleanup(func() { DefaultUsernamePolicy, DefaultEmailPolicy = username, email })
+	DefaultUsernamePolicy.MaxLength = 8
+	DefaultEmailPolicy.RejectDisposable = true
+
+	type account struct {
+		Username string `validate:"username"`
+		Email    string `validate:"email"`
+	}
+	err := ValidateStruct(account{Username: "jonathan_doe", Email: "jon@mailinator.com"})
+	if !errors.Is(err, ErrUsernameTooLong) || !errors.Is(err, ErrEmailDisposable) {
+		t.Errorf("ValidateStruct error = %v; want ErrUsernameTooLong and ErrEmailDisposable", err)
+	}
+}
+
+func TestValidateStructCollectionBounds(t *testing.T) {
+	type team struct {
+		Members []string          `validate:"min=2,max=3"`
+		Roles   map[string]string `validate:"max=2"`
+		Name    string            `validate:"max=3"`
+	}
+	err := ValidateStruct(team{
+		Members: []string{"ann"},
+		Roles:   map[string]string{"ann": "lead", "bob": "dev", "cy": "qa"},
+		Name:    "four",
+	})
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 3 {
+		t.Fatalf("error = %v; want 3 errors", err)
+	}
+
+	want := []struct {
+		code    string
+		err     error
+		message string
+	}{
+		{"too_few_items", ErrTooShort, "must have at least 2 items"},
+		{"too_many_items", ErrTooLong, "must have at most 2 items"},
+		{"too_long", ErrTooLong, "must be at most 3 characters long"},
+	}
+	for i, w := range want {
+		if errs[i].Code != w.code || !errors.Is(errs[i], w.err) {
+			t.Errorf("errs[%d] = {%s %v}; want {%s %v}", i, errs[i].Code, errs[i].Err, w.code, w.err)
+		}
+		if got := DefaultCatalog.Translate("en", errs[i]); got != w.message {
+			t.Errorf("errs[%d] message = %q; want %q", i, got, w.message)
+		}
+	}
+}
+
+type tagGraphNode struct {
+	Name     string `validate:"required"`
+	Next     *tagGraphNode
+	Children []*tagGraphNode
+	Links    map[string]any
+}
+
+func TestValidateStructCyclicGraph(t *testing.T) {
+	self := &tagGraphNode{}
+	self.Next = self
+	self.Children = []*tagGraphNode{self}
+	self.Links = map[string]any{"self": self.Links}
+	self.Links["self"] = self.Links
+
+	a, b := &tagGraphNode{Name: "a"}, &tagGraphNode{}
+	a.Next, b.Next = b, a
+
+	shared := &tagGraphNode{}
+	dag := tagGraphNode{Name: "root", Children: []*tagGraphNode{shared, shared}}
+
+	tests := []struct {
+		name  string
+		value any
+		paths []string
+	}{
+		{"self loop", self, []string{"Name"}},
+		{"two-node cycle", a, []string{"Next.Name"}},
+		{"shared node", dag, []string{"Children[0].Name", "Children[1].Name"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errs ValidationErrors
+			if !errors.As(ValidateStruct(tt.value), &errs) {
+				t.Fatalf("ValidateStruct returned no ValidationErrors")
+			}
+			var paths []string
+			for _, fe := range errs {
+				paths = append(paths, fe.Path)
+			}
+			if !slices.Equal(paths, tt.paths) {
+				t.Errorf("error paths = %v; want %v", paths, tt.paths)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package basics
+
+import "strings"
+
+// TraceStep records a single reduction performed while evaluating an
+// expression.
+type TraceStep struct {
+	// Operation is the Calculator method that performed the step, such as
+	// "Multiply" or "MatrixAdd".
+	Operation string   `json:"operation"`
+	Operands  []string `json:"operands"`
+	Result    string   `json:"result,omitempty"`
+	// Expression is the whole expression after this step was applied.
+	Expression string `json:"expression,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+func (s TraceStep) String() string {
+	call := s.Operation + "(" + strings.Join(s.Operands, ", ") + ")"
+	if s.Error != "" {
+		return call + " failed: " + s.Error
+	}
+	return call + " = " + s.Result
+}
+
+// Trace is the step-by-step record produced by EvaluateTrace. It marshals to
+// JSON directly; String renders it as plain text.
+type Trace struct {
+	Expression string      `json:"expression"`
+	Steps      []TraceStep `json:"steps"`
+	Result     string      `json:"result,omitempty"`
+	Error      string      `json:"error,omitempty"`
+}
+
+// String renders the trace as a chain of reductions, for example
+// "3 + 4 * 2 → 3 + 8 → 11". A failing step ends the chain with the
+// operation and its error.
+func (t *Trace) String() string {
+	parts := []string{t.Expression}
+	for _, step := range t.Steps {
+		if step.Error != "" {
+			parts = append(parts, step.String())
+			break
+		}
+		parts = append(parts, step.Expression)
+	}
+	if len(t.Steps) == 0 && t.Error != "" {
+		parts = append(parts, "error: "+t.Error)
+	}
+	return strings.Join(parts, " → ")
+}
+
+// EvaluateTrace evaluates expr like Evaluate, reducing one operation at a
+// time and recording each step. When an operation fails the trace ends with
+// the failing step and the error is returned alongside the trace.
+func (c *Calculator) EvaluateTrace(expr string) (*Trace, error) {
+	root, err := parseExpression(expr)
+	if err != nil {
+		return &Trace{Expression: strings.TrimSpace(expr), Error: err.Error()}, err
+	}
+
+	trace := &Trace{Expression: renderNode(root)}
+	for {
+		if lit, ok := root.(literalNode); ok {
+			trace.Result = FormatValue(lit.value)
+			return trace, nil
+		}
+
+		next, step, err := c.reduce(root)
+		if err != nil {
+			trace.Steps = append(trace.Steps, step)
+			trace.Error = err.Error()
+			return trace, err
+		}
+
+		root = next
+		step.Expression = renderNode(root)
+		trace.Steps = append(trace.Steps, step)
+	}
+}
+
+// reduce applies the first operation whose operands are all literals, in
+// the same left-to-right order that eval uses, and returns the rewritten
+// tree.
+func (c *Calculator) reduce(n exprNode) (exprNode, TraceStep, error) {
+	children := childNodes(n)
+	for i, child := range children {
+		if _, ok := child.(literalNode); ok {
+			continue
+		}
+
+		reduced, step, err := c.reduce(child)
+		if err != nil {
+			return nil, step, err
+		}
+		return withChild(n, i, reduced), step, nil
+	}
+
+	args := make([]any, len(children))
+	step := TraceStep{Operands: make([]string, len(children))}
+	for i, child := range children {
+		args[i] = child.(literalNode).value
+		step.Operands[i] = FormatValue(args[i])
+	}
+
+	value, op, err := c.apply(n, args)
+	step.Operation = op
+	if step.Operation == "" {
+		step.Operation = renderOperator(n)
+	}
+	if err != nil {
+		step.Error = err.Error()
+		return nil, step, err
+	}
+
+	step.Result = FormatValue(value)
+	return literalNode{value: value}, step, nil
+}
+
+// withChild returns a copy of n with its i-th operand replaced.
+func withChild(n exprNode, i int, child exprNode) exprNode {
+	switch n := n.(type) {
+	case negateNode:
+		n.operand = child
+		return n
+	case binaryNode:
+		if i == 0 {
+			n.left = child
+		} else {
+			n.right = child
+		}
+		return n
+	case callNode:
+		args := append([]exprNode(nil), n.args...)
+		args[i] = child
+		n.args = args
+		return n
+	}
+	return n
+}
+
+func renderOperator(n exprNode) string {
+	switch n := n.(type) {
+	case negateNode:
+		return "-"
+	case binaryNode:
+		return string(n.op)
+	case callNode:
+		return n.name
+	}
+	return ""
+}
+
+// Operator precedence used when rendering, from loosest to tightest.
+const (
+	precSum = iota + 1
+	precProduct
+	precUnary
+	precPower
+	precPrimary
+)
+
+func precedence(n exprNode) int {
+	switch n := n.(type) {
+	case literalNode:
+		if strings.HasPrefix(FormatValue(n.value), "-") {
+			return precUnary
+		}
+		return precPrimary
+	case negateNode:
+		return precUnary
+	case binaryNode:
+		switch n.op {
+		case '+', '-':
+			return precSum
+		case '*', '/':
+			return precProduct
+		}
+		return precPower
+	}
+	return precPrimary
+}
+
+// renderNode prints an expression tree with the minimum parentheses needed
+// to preserve its structure.
+func renderNode(n exprNode) string {
+	switch n := n.(type) {
+	case literalNode:
+		return FormatValue(n.value)
+	case negateNode:
+		// Keep "-(3)" distinct from the literal -3 so the negation step shows.
+		_, isLiteral := n.operand.(literalNode)
+		return "-" + renderOperand(n.operand, isLiteral || precedence(n.operand) <= precUnary)
+	case binaryNode:
+		prec := precedence(n)
+		var left, right string
+		if n.op == '^' {
+			// Right-associative: only the base needs extra care.
+			left = renderOperand(n.left, precedence(n.left) <= prec)
+			right = renderOperand(n.right, precedence(n.right) < precUnary)
+			return left + " ^ " + right
+		}
+		left = renderOperand(n.left, precedence(n.left) < prec)
+		right = renderOperand(n.right, precedence(n.right) <= prec)
+		return left + " " + string(n.op) + " " + right
+	case callNode:
+		args := make([]string, len(n.args))
+		for i, arg := range n.args {
+			args[i] = renderNode(arg)
+		}
+		return n.name + "(" + strings.Join(args, ", ") + ")"
+	}
+	return ""
+}
+
+func renderOperand(n exprNode, parenthesize bool) string {
+	if parenthesize {
+		return "(" + renderNode(n) + ")"
+	}
+	return renderNode(n)
+}
@@ -0,0 +1,106 @@
+package basics
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestEvaluateTrace(t *testing.T) {
+	calc := Calculator{}
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"precedence", "3 + 4 * 2", "3 + 4 * 2 → 3 + 8 → 11"},
+		{"parentheses kept until reduced", "(1 + 2) * (3 + 4)", "(1 + 2) * (3 + 4) → 3 * (3 + 4) → 3 * 7 → 21"},
+		{"left associative subtraction", "10 - (2 - 1)", "10 - (2 - 1) → 10 - 1 → 9"},
+		{"power", "2 ^ 3 ^ 2", "2 ^ 3 ^ 2 → 2 ^ 9 → 512"},
+		{"negation", "-(1 + 2)", "-(1 + 2) → -(3) → -3"},
+		{"function", "det([[1, 2], [3, 4]]) + 1", "det([[1, 2], [3, 4]]) + 1 → -2 + 1 → -1"},
+		{"literal", "42", "42"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trace, err := calc.EvaluateTrace(tt.input)
+			if err != nil {
+				t.Fatalf("EvaluateTrace(%q) unexpected error: %v", tt.input, err)
+			}
+			if got := trace.String(); got != tt.want {
+				t.Errorf("EvaluateTrace(%q) = %q; want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateTraceOperations(t *testing.T) {
+	calc := Calculator{}
+	trace, err := calc.EvaluateTrace("3 + 4 * 2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []TraceStep{
+		{Operation: "Multiply", Operands: []string{"4", "2"}, Result: "8", Expression: "3 + 8"},
+		{Operation: "Add", Operands: []string{"3", "8"}, Result: "11", Expression: "11"},
+	}
+	if len(trace.Steps) != len(want) {
+		t.Fatalf("got %d steps; want %d", len(trace.Steps), len(want))
+	}
+	for i, step := range trace.Steps {
+		if step.String() != want[i].String() || step.Expression != want[i].Expression {
+			t.Errorf("step %d = %s → %q; want %s → %q", i, step, step.Expression, want[i], want[i].Expression)
+		}
+	}
+	if trace.Result != "11" {
+		t.Errorf("Result = %q; want %q", trace.Result, "11")
+	}
+}
+
+func TestEvaluateTraceDivideError(t *testing.T) {
+	calc := Calculator{}
+	trace, err := calc.EvaluateTrace("10 / (5 - 5)")
+
+	if !errors.Is(err, ErrDivisionByZero) {
+		t.Fatalf("error = %v; want ErrDivisionByZero", err)
+	}
+
+	want := "10 / (5 - 5) → 10 / 0 → Divide(10, 0) failed: division by zero"
+	if got := trace.String(); got != want {
+		t.Errorf("trace = %q; want %q", got, want)
+	}
+
+	last := trace.Steps[len(trace.Steps)-1]
+	if last.Operation != "Divide" || last.Error != "division by zero" {
+		t.Errorf("last step = %+v; want failed Divide", last)
+	}
+}
+
+func TestEvaluateTraceJSON(t *testing.T) {
+	calc := Calculator{}
+	trace, _ := calc.EvaluateTrace("1 / 0")
+
+	data, err := json.Marshal(trace)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+
+	want := `{"expression":"1 / 0","steps":[{"operation":"Divide","operands":["1","0"],"error":"division by zero"}],"error":"division by zero"}`
+	if string(data) != want {
+		t.Errorf("JSON = %s; want %s", data, want)
+	}
+}
+
+func TestEvaluateTraceParseError(t *testing.T) {
+	calc := Calculator{}
+	trace, err := calc.EvaluateTrace("1 +")
+
+	if !errors.Is(err, ErrInvalidExpression) {
+		t.Fatalf("error = %v; want ErrInvalidExpression", err)
+	}
+	if len(trace.Steps) != 0 || trace.Error == "" {
+		t.Errorf("trace = %+v; want no steps and an error", trace)
+	}
+}
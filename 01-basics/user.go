@@ -1,79 +1,199 @@
 package basics
 
 import (
+	"context"
 	"errors"
-	"regexp"
+	"fmt"
 	"strings"
+	"time"
 )
 
+// User is validated by ValidateUser. Its `validate` tags describe the same
+// rules for ValidateStruct and JSONSchema.
 type User struct {
-	Username string
-	Email    string
-	Age      int
+	Username string `validate:"required,username"`
+	Email    string `validate:"required,email"`
+	Age      int    `validate:"age"`
+	// DateOfBirth is optional. When set, it is validated by
+	// DefaultAgePolicy and takes precedence over Age.
+	DateOfBirth time.Time
+	// Password is the plain-text password chosen at sign-up. It is only
+	// validated when set and is never copied into validation errors.
+	Password string `validate:"omitempty,password"`
+	// Phone is optional. It must include its country code; store it in
+	// the form returned by NormalizePhone.
+	Phone string `validate:"omitempty,phone"`
 }
 
+// CurrentAge returns the user's age according to clock, computed from
+// DateOfBirth if it is set and taken from Age otherwise.
+func (u User) CurrentAge(clock Clock) int {
+	if u.DateOfBirth.IsZero() {
+		return u.Age
+	}
+	return AgeAt(u.DateOfBirth, clock.Now())
+}
+
+// minAge and maxAge bound User.Age, for AgeRules and the "age" tag alike.
+const (
+	minAge = 0
+	maxAge = 150
+)
+
 var (
 	ErrEmptyUsername    = errors.New("username cannot be empty")
 	ErrInvalidEmail     = errors.New("invalid email format")
-	ErrInvalidAge       = errors.New("age must be between 0 and 150")
-	ErrUsernameTooShort = errors.New("username must be at least 3 characters")
-	ErrUsernameTooLong  = errors.New("username must be less than 30 characters")
+	ErrInvalidAge       = fmt.Errorf("age must be between %d and %d", minAge, maxAge)
+	ErrUsernameTooShort = errors.New("username is too short")
+	ErrUsernameTooLong  = errors.New("username is too long")
+
+	ErrEmailContainsUsername = errors.New("email must not contain the username")
 )
 
-var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
+var (
+	UsernameRules = RuleSet[string]{
+		&DefaultUsernamePolicy,
+	}
+	EmailRules = RuleSet[string]{
+		Trimmed(
+			Required(ErrInvalidEmail),
+			&DefaultEmailPolicy,
+		),
+	}
+	AgeRules = RuleSet[int]{
+		Between(minAge, maxAge, ErrInvalidAge),
+	}
+	PhoneRules = RuleSet[string]{
+		&DefaultPhonePolicy,
+	}
+)
 
-func ValidateUser(u User) error {
-	if err := ValidateUsername(u.Username); err != nil {
-		return err
+// EmailNotContainingUsername is a cross-field rule rejecting users whose
+// email address contains their username, compared case-insensitively.
+var EmailNotContainingUsername = RuleFunc[User](func(u User) error {
+	username := SanitizeUsername(u.Username)
+	if username != "" && strings.Contains(NormalizeUsername(u.Email), username) {
+		return NewFieldError("Email", u.Email, ErrEmailContainsUsername)
 	}
-	if err := ValidateEmail(u.Email); err != nil {
-		return err
+	return nil
+})
+
+// UserPasswordRule checks a non-empty password against
+// DefaultPasswordPolicy, including that it does not contain the username or
+// email address.
+var UserPasswordRule = RuleFunc[User](func(u User) error {
+	if u.Password == "" {
+		return nil
 	}
-	if err := ValidateAge(u.Age); err != nil {
-		return err
+	if err := DefaultPasswordPolicy.ValidateFor(u.Password, u.Username, u.Email); err != nil {
+		return NewFieldError("Password", nil, err)
 	}
 	return nil
+})
+
+// DefaultUserValidator returns a validator with the rules used by
+// ValidateUser. Callers may add their own rules to the returned validator.
+func DefaultUserValidator() *Validator[User] {
+	return NewValidator(
+		Field("Username", func(u User) string { return u.Username }, UsernameRules),
+		Field("Email", func(u User) string { return u.Email }, EmailRules),
+		Field("Age", func(u User) int { return u.Age }, AgeRules),
+		Field("DateOfBirth", func(u User) time.Time { return u.DateOfBirth },
+			When(func(dob time.Time) bool { return !dob.IsZero() }, Rule[time.Time](&DefaultAgePolicy))),
+		UserPasswordRule,
+		Field("Phone", func(u User) string { return u.Phone },
+			When(func(phone string) bool { return phone != "" }, Rule[string](PhoneRules))),
+	)
 }
 
-func ValidateUsername(username string) error {
-	username = strings.TrimSpace(username)
+var defaultUserValidator = DefaultUserValidator()
 
-	if username == "" {
-		return ErrEmptyUsername
-	}
+// ValidateUser checks every field of u. On failure it returns a
+// ValidationErrors with one entry per invalid field.
+func ValidateUser(u User) error {
+	return defaultUserValidator.Validate(u)
+}
 
-	if len(username) < 3 {
-		return ErrUsernameTooShort
+// UserAsyncValidator returns a validator running the rules of ValidateUser
+// and then checking the email domain's mail servers through r (nil means
+// DefaultResolver).
+func UserAsyncValidator(r Resolver) *AsyncValidator[User] {
+	mx := DefaultMXCheck
+	if r != nil {
+		mx.Resolver = r
 	}
+	return NewAsyncValidator(DefaultUserValidator(),
+		FieldContext("Email", func(u User) string { return u.Email }, ContextRule[string](mx)),
+	)
+}
 
-	if len(username) > 20 {
-		return ErrUsernameTooLong
-	}
+// ValidateUserContext is ValidateUser plus checks that need I/O, such as
+// whether the email domain accepts mail. It returns the same
+// ValidationErrors.
+func ValidateUserContext(ctx context.Context, u User) error {
+	return UserAsyncValidator(nil).ValidateContext(ctx, u)
+}
 
-	return nil
+func ValidateUsername(username string) error {
+	return UsernameRules.Validate(username)
 }
 
 func ValidateEmail(email string) error {
-	email = strings.TrimSpace(email)
-
-	if email == "" {
-		return ErrInvalidEmail
-	}
+	return EmailRules.Validate(email)
+}
 
-	if !emailRegex.MatchString(email) {
-		return ErrInvalidEmail
-	}
+func ValidateAge(age int) error {
+	return AgeRules.Validate(age)
+}
 
-	return nil
+// ValidatePassword checks password on its own against
+// DefaultPasswordPolicy.
+func ValidatePassword(password string) error {
+	return DefaultPasswordPolicy.Validate(password)
 }
 
-func ValidateAge(age int) error {
-	if age < 0 || age > 150 {
-		return ErrInvalidAge
-	}
-	return nil
+// ValidatePhone checks phone on its own against DefaultPhonePolicy.
+func ValidatePhone(phone string) error {
+	return PhoneRules.Validate(phone)
 }
 
+// SanitizeUsername returns the canonical form of username. See
+// NormalizeUsername for what it guarantees.
 func SanitizeUsername(username string) string {
-	return strings.ToLower(strings.TrimSpace(username))
+	return NormalizeUsername(username)
+}
+
+// SanitizeUserInput applies DefaultSanitizer to the username, email address
+// and phone number of u, as received from a form and before validation. It
+// returns the cleaned user and a report for each field in which unsafe
+// characters were found, including fields it rejected. Rejected fields are
+// left unchanged and listed in the returned ValidationErrors. The password
+// is used as typed, so it is not sanitized.
+func SanitizeUserInput(u User) (User, []SanitizeReport, error) {
+	var (
+		reports []SanitizeReport
+		errs    ValidationErrors
+	)
+	for _, field := range []struct {
+		name  string
+		value *string
+	}{
+		{"Username", &u.Username},
+		{"Email", &u.Email},
+		{"Phone", &u.Phone},
+	} {
+		clean, report, err := DefaultSanitizer.Sanitize(field.name, *field.value)
+		if len(report.Chars) > 0 {
+			reports = append(reports, report)
+		}
+		if err != nil {
+			errs = appendErrors(errs, err)
+			continue
+		}
+		*field.value = clean
+	}
+	if len(errs) > 0 {
+		return u, reports, errs
+	}
+	return u, reports, nil
 }
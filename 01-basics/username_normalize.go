@@ -0,0 +1,57 @@
+package basics
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizeUsername maps a username to the canonical form used to detect
+// duplicates. It applies, in order:
+//
+//  1. removal of invisible characters: zero-width spaces and joiners, soft
+//     hyphens, variation selectors, bidi marks and other format or control
+//     characters that are not whitespace;
+//  2. NFKC normalization, so compatibility variants such as full-width
+//     "ＡＬＩＣＥ" or the ligature "ﬁ" become their plain equivalents;
+//  3. full Unicode case folding, so "Straße" and "STRASSE" both become
+//     "strasse", followed by NFKC again since folding can denormalize;
+//  4. trimming and collapsing each run of whitespace to a single space.
+//
+// The result is idempotent: NormalizeUsername(NormalizeUsername(s)) ==
+// NormalizeUsername(s). Two usernames are duplicates exactly when they
+// normalize to the same string. Normalization does not make look-alikes from
+// different scripts equal; use Confusable for that.
+func NormalizeUsername(username string) string {
+	s := strings.Map(func(r rune) rune {
+		if isInvisible(r) {
+			return -1
+		}
+		return r
+	}, username)
+
+	s = norm.NFKC.String(s)
+	s = norm.NFKC.String(cases.Fold().String(s))
+	return strings.Join(strings.FieldsFunc(s, unicode.IsSpace), " ")
+}
+
+// SameUsername reports whether a and b normalize to the same username.
+func SameUsername(a, b string) bool {
+	return NormalizeUsername(a) == NormalizeUsername(b)
+}
+
+// isInvisible reports whether r renders as nothing, or should not appear in
+// a username at all. Whitespace is kept for the collapsing step.
+func isInvisible(r rune) bool {
+	if unicode.IsSpace(r) {
+		return false
+	}
+	return unicode.In(r,
+		unicode.Cc,
+		unicode.Cf,
+		unicode.Variation_Selector,
+		unicode.Other_Default_Ignorable_Code_Point,
+	)
+}
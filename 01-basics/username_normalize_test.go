@@ -0,0 +1,64 @@
+package basics
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeUsername(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"Straße", "strasse"},
+		{"STRASSE", "strasse"},
+		{"ＡＬＩＣＥ", "alice"},
+		{"ﬁnn", "finn"},
+		{"jo\u200bhn", "john"},
+		{"jo\u00adhn", "john"},
+		{"\u200ejohn\u200f", "john"},
+		{"heart\ufe0f", "heart"},
+		{"  Mary \t\u3000 Ann  ", "mary ann"},
+		{"ΣΊΣΥΦΟΣ", "σίσυφοσ"},
+		{"e\u0301", "\u00e9"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got := NormalizeUsername(tt.input)
+			if got != tt.want {
+				t.Errorf("NormalizeUsername(%q) = %q; want %q", tt.input, got, tt.want)
+			}
+			if again := NormalizeUsername(got); again != got {
+				t.Errorf("NormalizeUsername is not idempotent: %q -> %q", got, again)
+			}
+		})
+	}
+}
+
+func TestSameUsername(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"Straße", "STRASSE", true},
+		{"ａｌｉｃｅ", "Alice", true},
+		{"bo\u200db", "bob", true},
+		{"Jos\u00e9", "jose\u0301", true},
+		{"jose", "josé", false},
+		{"paypal", "pаypal", false},
+	}
+
+	for _, tt := range tests {
+		if got := SameUsername(tt.a, tt.b); got != tt.want {
+			t.Errorf("SameUsername(%q, %q) = %v; want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestReservedUsesNormalization(t *testing.T) {
+	if err := ValidateUsername("ＡＤＭＩＮ"); !errors.Is(err, ErrUsernameReserved) {
+		t.Errorf("ValidateUsername(full-width ADMIN) error = %v; want ErrUsernameReserved", err)
+	}
+}
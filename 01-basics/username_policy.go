@@ -0,0 +1,134 @@
+package basics
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	ErrUsernameInvalidChar           = errors.New("username contains characters that are not allowed")
+	ErrUsernameMustStartWithLetter   = errors.New("username must start with a letter")
+	ErrUsernameConsecutiveSeparators = errors.New("username must not contain consecutive separators")
+	ErrUsernameReserved              = errors.New("username is reserved")
+)
+
+// UsernamePolicy describes what a valid username looks like. Lengths are
+// counted in characters (runes), not bytes.
+type UsernamePolicy struct {
+	MinLength int
+	MaxLength int
+	// AllowedChars reports whether a non-separator rune may appear in a
+	// username. Nil allows Unicode letters and digits.
+	AllowedChars func(r rune) bool
+	// AllowedCharsClass describes AllowedChars as the contents of a regular
+	// expression character class, such as "A-Za-z0-9", for JSON Schema
+	// export. Without it, custom AllowedChars are not exported.
+	AllowedCharsClass string
+	// Separators lists punctuation allowed between name parts, e.g. "._-".
+	Separators              string
+	MustStartWithLetter     bool
+	NoConsecutiveSeparators bool
+	// SingleScript rejects names mixing scripts, such as Latin letters with
+	// Cyrillic look-alikes.
+	SingleScript bool
+	// Reserved names are rejected regardless of case.
+	Reserved []string
+	// Blocklist, if set, rejects names containing blocked terms.
+	Blocklist *Blocklist
+}
+
+// DefaultUsernamePolicy is the policy used by ValidateUsername.
+var DefaultUsernamePolicy = UsernamePolicy{
+	MinLength:               3,
+	MaxLength:               20,
+	Separators:              "._-",
+	MustStartWithLetter:     true,
+	NoConsecutiveSeparators: true,
+	SingleScript:            true,
+	Reserved:                []string{"admin", "root", "support"},
+	Blocklist:               DefaultBlocklist,
+}
+
+// ASCIILetterOrDigit restricts usernames to a-z, A-Z and 0-9 when used as
+// UsernamePolicy.AllowedChars.
+func ASCIILetterOrDigit(r rune) bool {
+	return r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+// Validate checks username against the policy after trimming surrounding
+// whitespace. Checks run from the most to the least fundamental, and the
+// first failure is returned.
+func (p UsernamePolicy) Validate(username string) error {
+	username = strings.TrimSpace(username)
+	if username == "" {
+		return ErrEmptyUsername
+	}
+
+	length := utf8.RuneCountInString(username)
+	if length < p.MinLength {
+		return &ParamError{
+			Err:     ErrUsernameTooShort,
+			Params:  map[string]any{"min": p.MinLength},
+			Message: fmt.Sprintf("%v (minimum %d characters)", ErrUsernameTooShort, p.MinLength),
+		}
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		return &ParamError{
+			Err:     ErrUsernameTooLong,
+			Params:  map[string]any{"max": p.MaxLength},
+			Message: fmt.Sprintf("%v (maximum %d characters)", ErrUsernameTooLong, p.MaxLength),
+		}
+	}
+
+	allowed := p.AllowedChars
+	if allowed == nil {
+		allowed = func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }
+	}
+
+	prevSeparator := false
+	for i, r := range username {
+		separator := strings.ContainsRune(p.Separators, r)
+		if !separator && !allowed(r) {
+			return &ParamError{
+				Err:     ErrUsernameInvalidChar,
+				Params:  map[string]any{"char": string(r)},
+				Message: fmt.Sprintf("%v: %q", ErrUsernameInvalidChar, r),
+			}
+		}
+		if i == 0 && p.MustStartWithLetter && !unicode.IsLetter(r) {
+			return ErrUsernameMustStartWithLetter
+		}
+		if separator && prevSeparator && p.NoConsecutiveSeparators {
+			return ErrUsernameConsecutiveSeparators
+		}
+		prevSeparator = separator
+	}
+
+	if p.SingleScript {
+		if err := SingleScript.Validate(username); err != nil {
+			return err
+		}
+	}
+
+	sanitized := SanitizeUsername(username)
+	for _, reserved := range p.Reserved {
+		if sanitized == SanitizeUsername(reserved) {
+			return &ParamError{
+				Err:     ErrUsernameReserved,
+				Params:  map[string]any{"value": username},
+				Message: fmt.Sprintf("%v: %q", ErrUsernameReserved, username),
+			}
+		}
+	}
+
+	if p.Blocklist != nil {
+		if err := p.Blocklist.Validate(username); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package basics
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDefaultUsernamePolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		username string
+		wantErr  error
+	}{
+		{"simple", "johndoe", nil},
+		{"separators", "john.doe-99", nil},
+		{"unicode letters", "zoë_müller", nil},
+		{"length counted in runes", "日本語ユーザー", nil},
+		{"twenty runes", "ääääääääääääääääääää", nil},
+		{"twenty-one runes", "äääääääääääääääääääää", ErrUsernameTooLong},
+		{"too short", "ab", ErrUsernameTooShort},
+		{"empty after trim", "   ", ErrEmptyUsername},
+		{"space inside", "john doe", ErrUsernameInvalidChar},
+		{"symbol", "john$doe", ErrUsernameInvalidChar},
+		{"starts with digit", "1john", ErrUsernameMustStartWithLetter},
+		{"starts with separator", "_john", ErrUsernameMustStartWithLetter},
+		{"consecutive separators", "john__doe", ErrUsernameConsecutiveSeparators},
+		{"mixed consecutive separators", "john.-doe", ErrUsernameConsecutiveSeparators},
+		{"reserved", "admin", ErrUsernameReserved},
+		{"reserved any case", " Root ", ErrUsernameReserved},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := DefaultUsernamePolicy.Validate(tt.username)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate(%q) error = %v; want %v", tt.username, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCustomUsernamePolicy(t *testing.T) {
+	policy := UsernamePolicy{
+		MinLength:    2,
+		MaxLength:    8,
+		AllowedChars: ASCIILetterOrDigit,
+		Separators:   "_",
+		Reserved:     []string{"staff"},
+	}
+
+	tests := []struct {
+		username string
+		wantErr  error
+	}{
+		{"jo", nil},
+		{"9lives", nil},
+		{"a__b", nil},
+		{"müller", ErrUsernameInvalidChar},
+		{"john.doe", ErrUsernameInvalidChar},
+		{"abcdefghi", ErrUsernameTooLong},
+		{"STAFF", ErrUsernameReserved},
+		{"admin", nil},
+	}
+
+	for _, tt := range tests {
+		err := policy.Validate(tt.username)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("Validate(%q) error = %v; want %v", tt.username, err, tt.wantErr)
+		}
+	}
+}
+
+func TestUsernamePolicyErrorMentionsLimit(t *testing.T) {
+	err := UsernamePolicy{MinLength: 5}.Validate("abc")
+	want := "username is too short (minimum 5 characters)"
+	if err == nil || err.Error() != want {
+		t.Errorf("error = %v; want %q", err, want)
+	}
+}
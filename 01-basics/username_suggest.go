@@ -0,0 +1,176 @@
+package basics
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var ErrNoUsernameSuggestions = errors.New("no username suggestions available")
+
+// maxSuggestionAttempts bounds how many candidates are generated, including
+// empty and repeated ones, so that a strict policy or a crowded namespace
+// cannot make Suggest loop forever.
+const maxSuggestionAttempts = 500
+
+// UsernameSuggester proposes alternative usernames during sign-up. Every
+// suggestion passes Policy and is reported available by Available.
+type UsernameSuggester struct {
+	Policy UsernamePolicy
+	// Available reports whether a username is free. Nil treats every
+	// username as available.
+	Available func(username string) (bool, error)
+	// Max is the number of suggestions to return. Zero means 5.
+	Max int
+}
+
+// SuggestionInput is what the user has told us so far. Any field may be
+// empty.
+type SuggestionInput struct {
+	Desired     string
+	Email       string
+	DisplayName string
+}
+
+// Suggest returns up to Max usernames, best first: the names derived from
+// the input as they are, then the same names with increasing numeric
+// suffixes. It returns ErrNoUsernameSuggestions if nothing suitable was
+// found and passes on errors from Available.
+func (s UsernameSuggester) Suggest(in SuggestionInput) ([]string, error) {
+	limit := s.Max
+	if limit <= 0 {
+		limit = 5
+	}
+
+	bases := s.bases(in)
+	if len(bases) == 0 {
+		return nil, ErrNoUsernameSuggestions
+	}
+
+	var suggestions []string
+	seen := make(map[string]bool)
+	attempts := 0
+	for n := 0; len(suggestions) < limit && attempts < maxSuggestionAttempts; n++ {
+		for _, base := range bases {
+			if len(suggestions) == limit || attempts == maxSuggestionAttempts {
+				break
+			}
+			attempts++
+			candidate := s.withSuffix(base, n)
+			key := SanitizeUsername(candidate)
+			if candidate == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			if s.Policy.Validate(candidate) != nil {
+				continue
+			}
+			if s.Available != nil {
+				ok, err := s.Available(candidate)
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					continue
+				}
+			}
+			suggestions = append(suggestions, candidate)
+		}
+	}
+
+	if len(suggestions) == 0 {
+		return nil, ErrNoUsernameSuggestions
+	}
+	return suggestions, nil
+}
+
+// bases derives candidate names from the input, most specific first.
+func (s UsernameSuggester) bases(in SuggestionInput) []string {
+	var raw []string
+	raw = append(raw, in.Desired)
+
+	if local, _, ok := strings.Cut(in.Email, "@"); ok {
+		// Drop sub-addressing such as "jane+news".
+		local, _, _ = strings.Cut(local, "+")
+		raw = append(raw, local)
+	}
+
+	words := strings.Fields(NormalizeUsername(in.DisplayName))
+	if len(words) > 0 {
+		first, last := words[0], words[len(words)-1]
+		for _, sep := range s.Policy.Separators {
+			raw = append(raw, strings.Join(words, string(sep)))
+		}
+		raw = append(raw, strings.Join(words, ""))
+		if len(words) > 1 {
+			initial, _ := utf8.DecodeRuneInString(first)
+			raw = append(raw, string(initial)+last, first)
+		}
+	}
+
+	var bases []string
+	for _, r := range raw {
+		if base := s.clean(r); base != "" && !slices.Contains(bases, base) {
+			bases = append(bases, base)
+		}
+	}
+	return bases
+}
+
+// clean turns arbitrary text into something shaped like a username under
+// the policy: normalized, with disallowed characters dropped, spaces turned
+// into the first separator and separators never doubled or at the ends.
+func (s UsernameSuggester) clean(text string) string {
+	allowed := s.Policy.AllowedChars
+	if allowed == nil {
+		allowed = func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }
+	}
+	defaultSep, _ := utf8.DecodeRuneInString(s.Policy.Separators)
+
+	var b strings.Builder
+	pendingSep := rune(0)
+	for _, r := range NormalizeUsername(text) {
+		switch {
+		case unicode.IsSpace(r) && s.Policy.Separators != "":
+			pendingSep = defaultSep
+		case strings.ContainsRune(s.Policy.Separators, r):
+			pendingSep = r
+		case allowed(r):
+			if b.Len() == 0 && s.Policy.MustStartWithLetter && !unicode.IsLetter(r) {
+				continue
+			}
+			if pendingSep != 0 && b.Len() > 0 {
+				b.WriteRune(pendingSep)
+			}
+			pendingSep = 0
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// withSuffix appends n to base, shortening base so the result fits within
+// the policy's maximum length. n == 0 means no suffix.
+func (s UsernameSuggester) withSuffix(base string, n int) string {
+	suffix := ""
+	if n > 0 {
+		suffix = strconv.Itoa(n)
+	}
+	runes := []rune(base)
+	if s.Policy.MaxLength > 0 && len(runes)+len(suffix) > s.Policy.MaxLength {
+		keep := s.Policy.MaxLength - len(suffix)
+		if keep <= 0 {
+			return ""
+		}
+		runes = runes[:keep]
+		// Never leave a separator dangling before the suffix.
+		for len(runes) > 0 && strings.ContainsRune(s.Policy.Separators, runes[len(runes)-1]) {
+			runes = runes[:len(runes)-1]
+		}
+	}
+	return string(runes) + suffix
+}
@@ -0,0 +1,134 @@
+package basics
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func takenLookup(taken ...string) func(string) (bool, error) {
+	return func(username string) (bool, error) {
+		return !slices.Contains(taken, SanitizeUsername(username)), nil
+	}
+}
+
+func TestUsernameSuggesterSuggest(t *testing.T) {
+	s := UsernameSuggester{
+		Policy:    DefaultUsernamePolicy,
+		Available: takenLookup("jane", "jane.doe"),
+	}
+
+	got, err := s.Suggest(SuggestionInput{
+		Desired:     "Jane",
+		Email:       "jane.doe+news@example.com",
+		DisplayName: "Jane Doe",
+	})
+	if err != nil {
+		t.Fatalf("Suggest returned error: %v", err)
+	}
+
+	want := []string{"jane_doe", "jane-doe", "janedoe", "jdoe", "jane1"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Suggest = %v; want %v", got, want)
+	}
+}
+
+func TestUsernameSuggesterAlwaysValid(t *testing.T) {
+	policy := DefaultUsernamePolicy
+	policy.MaxLength = 8
+
+	s := UsernameSuggester{Policy: policy, Max: 10}
+	inputs := []SuggestionInput{
+		{Desired: "  Zoë__Müller!! "},
+		{Desired: "42lives"},
+		{Email: "9.lives@example.com"},
+		{DisplayName: "Ａｌｅｘａｎｄｅｒ von Humboldt-Straße"},
+		{Desired: "ab"},
+	}
+
+	for _, in := range inputs {
+		got, err := s.Suggest(in)
+		if err != nil {
+			t.Errorf("Suggest(%+v) returned error: %v", in, err)
+			continue
+		}
+		if len(got) != s.Max {
+			t.Errorf("Suggest(%+v) returned %d suggestions; want %d", in, len(got), s.Max)
+		}
+		for _, name := range got {
+			if err := policy.Validate(name); err != nil {
+				t.Errorf("suggestion %q from %+v is invalid: %v", name, in, err)
+			}
+		}
+	}
+}
+
+func TestUsernameSuggesterShortName(t *testing.T) {
+	s := UsernameSuggester{Policy: DefaultUsernamePolicy, Max: 2}
+	got, err := s.Suggest(SuggestionInput{Desired: "al"})
+	if err != nil {
+		t.Fatalf("Suggest returned error: %v", err)
+	}
+	if want := []string{"al1", "al2"}; !slices.Equal(got, want) {
+		t.Errorf("Suggest(al) = %v; want %v", got, want)
+	}
+}
+
+func TestUsernameSuggesterTruncatesForSuffix(t *testing.T) {
+	policy := DefaultUsernamePolicy
+	policy.MaxLength = 6
+	s := UsernameSuggester{Policy: policy, Available: takenLookup("jane_d"), Max: 1}
+
+	got, err := s.Suggest(SuggestionInput{Desired: "jane_doe"})
+	if err != nil {
+		t.Fatalf("Suggest returned error: %v", err)
+	}
+	// "jane_d" is taken and "jane_" + "1" would end in a separator.
+	if want := []string{"jane1"}; !slices.Equal(got, want) {
+		t.Errorf("Suggest = %v; want %v", got, want)
+	}
+}
+
+func TestUsernameSuggesterErrors(t *testing.T) {
+	s := UsernameSuggester{Policy: DefaultUsernamePolicy}
+	if _, err := s.Suggest(SuggestionInput{Desired: "!!!"}); !errors.Is(err, ErrNoUsernameSuggestions) {
+		t.Errorf("Suggest(!!!) error = %v; want ErrNoUsernameSuggestions", err)
+	}
+
+	// Every variant of a blocked name is blocked too.
+	if _, err := s.Suggest(SuggestionInput{Desired: "admin"}); !errors.Is(err, ErrNoUsernameSuggestions) {
+		t.Errorf("Suggest(admin) error = %v; want ErrNoUsernameSuggestions", err)
+	}
+
+	s.Available = func(string) (bool, error) { return false, nil }
+	if _, err := s.Suggest(SuggestionInput{Desired: "jane"}); !errors.Is(err, ErrNoUsernameSuggestions) {
+		t.Errorf("Suggest with nothing available error = %v; want ErrNoUsernameSuggestions", err)
+	}
+
+	errLookup := errors.New("database unavailable")
+	s.Available = func(string) (bool, error) { return false, errLookup }
+	if _, err := s.Suggest(SuggestionInput{Desired: "jane"}); !errors.Is(err, errLookup) {
+		t.Errorf("Suggest with failing lookup error = %v; want %v", err, errLookup)
+	}
+}
+
+func TestUsernameSuggesterTerminatesWithoutRoom(t *testing.T) {
+	s := UsernameSuggester{
+		Policy:    UsernamePolicy{MinLength: 1, MaxLength: 3},
+		Available: func(string) (bool, error) { return false, nil },
+	}
+	done := make(chan error, 1)
+	go func() {
+		_, err := s.Suggest(SuggestionInput{Desired: "jane"})
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrNoUsernameSuggestions) {
+			t.Errorf("Suggest error = %v; want ErrNoUsernameSuggestions", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Suggest did not return")
+	}
+}
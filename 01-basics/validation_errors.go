@@ -0,0 +1,241 @@
+package basics
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+)
+
+// FieldError describes why a single field failed validation. It wraps the
+// underlying error, so errors.Is works against the package sentinels.
+type FieldError struct {
+	// Path locates the field, e.g. "Email" or "Addresses[2].Zip". It is
+	// empty for errors that do not belong to a single field.
+	Path    string
+	Code    string
+	Message string
+	Value   any
+	// Params holds values for message templates, e.g. "min" for a length
+	// limit. See ParamError.
+	Params map[string]any
+	Err    error
+}
+
+// NewFieldError builds a FieldError for err, filling in its code and
+// message. Custom rules can return it to attribute an error to a field.
+func NewFieldError(path string, value any, err error) *FieldError {
+	fe := &FieldError{
+		Path:    path,
+		Code:    ErrorCode(err),
+		Message: err.Error(),
+		Value:   value,
+		Err:     err,
+	}
+	var pe *ParamError
+	if errors.As(err, &pe) {
+		fe.Params = pe.Params
+	}
+	return fe
+}
+
+func (e *FieldError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+func (e *FieldError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Field   string         `json:"field"`
+		Code    string         `json:"code"`
+		Message string         `json:"message"`
+		Value   any            `json:"value"`
+		Params  map[string]any `json:"params,omitempty"`
+	}{e.Path, e.Code, e.Message, e.Value, e.Params})
+}
+
+// ValidationErrors collects every failing field. errors.Is reports true if
+// any of the contained errors matches.
+type ValidationErrors []*FieldError
+
+func (v ValidationErrors) Error() string {
+	msgs := make([]string, len(v))
+	for i, e := range v {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (v ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(v))
+	for i, e := range v {
+		errs[i] = e
+	}
+	return errs
+}
+
+// MarshalJSON renders the errors as {"errors": [...]} for API responses.
+func (v ValidationErrors) MarshalJSON() ([]byte, error) {
+	list := []*FieldError(v)
+	if list == nil {
+		list = []*FieldError{}
+	}
+	return json.Marshal(struct {
+		Errors []*FieldError `json:"errors"`
+	}{list})
+}
+
+// ForField returns the errors recorded for the given path.
+func (v ValidationErrors) ForField(path string) []*FieldError {
+	var out []*FieldError
+	for _, e := range v {
+		if e.Path == path {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// prefixed returns a copy of err with parent prepended to its field paths.
+func prefixed(parent string, err error) error {
+	join := func(path string) string {
+		switch {
+		case path == "":
+			return parent
+		case strings.HasPrefix(path, "["):
+			return parent + path
+		}
+		return parent + "." + path
+	}
+
+	var list ValidationErrors
+	if errors.As(err, &list) {
+		out := make(ValidationErrors, len(list))
+		for i, e := range list {
+			copied := *e
+			copied.Path = join(e.Path)
+			out[i] = &copied
+		}
+		return out
+	}
+
+	var fe *FieldError
+	if errors.As(err, &fe) {
+		copied := *fe
+		copied.Path = join(fe.Path)
+		return &copied
+	}
+	return err
+}
+
+// appendErrors flattens err into list, wrapping plain errors in a
+// FieldError with an empty path.
+func appendErrors(list ValidationErrors, err error) ValidationErrors {
+	var nested ValidationErrors
+	if errors.As(err, &nested) {
+		return append(list, nested...)
+	}
+	var fe *FieldError
+	if errors.As(err, &fe) {
+		return append(list, fe)
+	}
+	return append(list, NewFieldError("", nil, err))
+}
+
+var (
+	errorCodesMu sync.RWMutex
+	errorCodes   = []struct {
+		err  error
+		code string
+	}{
+		{ErrEmptyUsername, "username.empty"},
+		{ErrUsernameTooShort, "username.too_short"},
+		{ErrUsernameTooLong, "username.too_long"},
+		{ErrUsernameInvalidChar, "username.invalid_char"},
+		{ErrUsernameMustStartWithLetter, "username.must_start_with_letter"},
+		{ErrUsernameConsecutiveSeparators, "username.consecutive_separators"},
+		{ErrUsernameReserved, "username.reserved"},
+		{ErrUsernameMixedScript, "username.mixed_script"},
+		{ErrUsernameConfusable, "username.confusable"},
+		{ErrUsernameBlocked, "username.blocked"},
+		{ErrEmailMissingAt, "email.missing_at"},
+		{ErrEmailLocalPart, "email.invalid_local_part"},
+		{ErrEmailDomain, "email.invalid_domain"},
+		{ErrEmailTooLong, "email.too_long"},
+		{ErrEmailDisplayName, "email.display_name"},
+		{ErrEmailNonASCII, "email.non_ascii"},
+		{ErrEmailContainsUsername, "email.contains_username"},
+		{ErrEmailDisposable, "email.disposable"},
+		{ErrEmailRoleAccount, "email.role_account"},
+		{ErrEmailUndeliverable, "email.undeliverable"},
+		{ErrEmailDomainUnverified, "email.unverified"},
+		// After the specific email errors, which wrap it.
+		{ErrInvalidEmail, "email.invalid"},
+		{ErrInvalidAge, "age.invalid"},
+		{ErrDateOfBirthRequired, "dob.required"},
+		{ErrDateOfBirthInFuture, "dob.future"},
+		{ErrImplausibleAge, "age.implausible"},
+		{ErrUnderAge13, "age.under_13"},
+		{ErrUnderAge16, "age.under_16"},
+		{ErrUnderAge18, "age.under_18"},
+		{ErrUnderMinimumAge, "age.under_minimum"},
+		{ErrPasswordRequired, "password.required"},
+		{ErrPasswordTooShort, "password.too_short"},
+		{ErrPasswordTooLong, "password.too_long"},
+		{ErrPasswordMissingClass, "password.missing_class"},
+		{ErrPasswordCommon, "password.common"},
+		{ErrPasswordContainsUsername, "password.contains_username"},
+		{ErrPasswordContainsEmail, "password.contains_email"},
+		{ErrPasswordTooWeak, "password.too_weak"},
+		{ErrDuplicateUsername, "username.duplicate"},
+		{ErrDuplicateEmail, "email.duplicate"},
+		{ErrImportInvalidValue, "import.invalid_value"},
+		{ErrImportFormat, "import.format"},
+		{ErrUnsafeChar, "unsafe_char"},
+		{ErrPhoneRequired, "phone.required"},
+		{ErrPhoneInvalidChar, "phone.invalid_char"},
+		{ErrPhoneCountryRequired, "phone.country_required"},
+		{ErrPhoneInvalidCountryCode, "phone.invalid_country_code"},
+		{ErrPhoneTooShort, "phone.too_short"},
+		{ErrPhoneTooLong, "phone.too_long"},
+		{ErrPhoneInvalidNumber, "phone.invalid"},
+		{ErrRequired, "required"},
+		{ErrTooFewItems, "too_few_items"},
+		{ErrTooManyItems, "too_many_items"},
+		{ErrTooShort, "too_short"},
+		{ErrTooLong, "too_long"},
+		{ErrTooSmall, "too_small"},
+		{ErrTooLarge, "too_large"},
+		{ErrNotOneOf, "not_one_of"},
+	}
+)
+
+// RegisterErrorCode assigns a stable machine-readable code to a sentinel
+// error so that FieldError.Code is meaningful for custom rules.
+func RegisterErrorCode(err error, code string) {
+	errorCodesMu.Lock()
+	defer errorCodesMu.Unlock()
+	errorCodes = append(errorCodes, struct {
+		err  error
+		code string
+	}{err, code})
+}
+
+// ErrorCode returns the code registered for the first sentinel that err
+// matches, or "invalid" if none is registered.
+func ErrorCode(err error) string {
+	errorCodesMu.RLock()
+	defer errorCodesMu.RUnlock()
+	for _, entry := range errorCodes {
+		if errors.Is(err, entry.err) {
+			return entry.code
+		}
+	}
+	return "invalid"
+}
@@ -0,0 +1,105 @@
+package basics
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestValidateUserReportsAllErrors(t *testing.T) {
+	err := ValidateUser(User{Username: "jo", Email: "not-an-email", Age: 200})
+
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("error = %v (%T); want ValidationErrors", err, err)
+	}
+
+	want := []struct {
+		path string
+		code string
+		err  error
+	}{
+		{"Username", "username.too_short", ErrUsernameTooShort},
+		{"Email", "email.missing_at", ErrEmailMissingAt},
+		{"Age", "age.invalid", ErrInvalidAge},
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("got %d errors (%v); want %d", len(errs), errs, len(want))
+	}
+	for i, w := range want {
+		if errs[i].Path != w.path || errs[i].Code != w.code || !errors.Is(errs[i], w.err) {
+			t.Errorf("errs[%d] = {%s %s %v}; want {%s %s %v}", i, errs[i].Path, errs[i].Code, errs[i].Err, w.path, w.code, w.err)
+		}
+		if !errors.Is(err, w.err) {
+			t.Errorf("errors.Is(err, %v) = false; want true", w.err)
+		}
+	}
+
+	if errs[2].Value != 200 {
+		t.Errorf("Age value = %v; want 200", errs[2].Value)
+	}
+	if got := errs.ForField("Email"); len(got) != 1 {
+		t.Errorf("ForField(Email) returned %d errors; want 1", len(got))
+	}
+}
+
+func TestValidationErrorsJSON(t *testing.T) {
+	err := ValidateUser(User{Username: "", Email: "john@example.com", Age: -1})
+
+	data, jerr := json.Marshal(err)
+	if jerr != nil {
+		t.Fatalf("json.Marshal: %v", jerr)
+	}
+
+	want := `{"errors":[` +
+		`{"field":"Username","code":"username.empty","message":"username cannot be empty","value":""},` +
+		`{"field":"Age","code":"age.invalid","message":"age must be between 0 and 150","value":-1,"params":{"max":150,"min":0}}]}`
+	if string(data) != want {
+		t.Errorf("JSON = %s; want %s", data, want)
+	}
+}
+
+func TestValidationErrorsMessage(t *testing.T) {
+	err := ValidateUser(User{Username: "", Email: "", Age: 1})
+	want := "Username: username cannot be empty; Email: invalid email format"
+	if err == nil || err.Error() != want {
+		t.Errorf("Error() = %v; want %q", err, want)
+	}
+}
+
+type address struct {
+	Zip string
+}
+
+type customer struct {
+	Name    string
+	Address address
+}
+
+func TestNestedFieldPaths(t *testing.T) {
+	errZip := errors.New("zip must have 5 digits")
+	RegisterErrorCode(errZip, "zip.length")
+
+	addressValidator := NewValidator(
+		Field("Zip", func(a address) string { return a.Zip }, MinLength(5, errZip)),
+	)
+	v := NewValidator(
+		Field("Name", func(c customer) string { return c.Name }, Required(ErrEmptyUsername)),
+		Field("Address", func(c customer) address { return c.Address }, Rule[address](addressValidator)),
+	)
+
+	err := v.Validate(customer{Name: "Ann", Address: address{Zip: "123"}})
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 1 {
+		t.Fatalf("error = %v; want one ValidationErrors entry", err)
+	}
+	if errs[0].Path != "Address.Zip" || errs[0].Code != "zip.length" || errs[0].Value != "123" {
+		t.Errorf("error = %+v; want Address.Zip zip.length 123", errs[0])
+	}
+}
+
+func TestErrorCodeUnknown(t *testing.T) {
+	if got := ErrorCode(errors.New("something else")); got != "invalid" {
+		t.Errorf("ErrorCode(unknown) = %q; want %q", got, "invalid")
+	}
+}
@@ -0,0 +1,166 @@
+package basics
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Rule checks a value and returns an error describing why it is invalid,
+// or nil if it is valid.
+type Rule[T any] interface {
+	Validate(value T) error
+}
+
+// RuleFunc adapts an ordinary function to the Rule interface.
+type RuleFunc[T any] func(value T) error
+
+func (f RuleFunc[T]) Validate(value T) error {
+	return f(value)
+}
+
+// RuleSet is a reusable, ordered group of rules. It stops at the first
+// failing rule, so later rules can assume earlier ones passed.
+type RuleSet[T any] []Rule[T]
+
+func (s RuleSet[T]) Validate(value T) error {
+	for _, rule := range s {
+		if err := rule.Validate(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// When applies rules only if cond reports true for the value.
+func When[T any](cond func(T) bool, rules ...Rule[T]) Rule[T] {
+	return RuleFunc[T](func(value T) error {
+		if !cond(value) {
+			return nil
+		}
+		return RuleSet[T](rules).Validate(value)
+	})
+}
+
+type fieldRule[T, F any] struct {
+	name  string
+	get   func(T) F
+	rules RuleSet[F]
+}
+
+// Validate reports failures as a FieldError whose path starts with the
+// field name. Paths from nested field rules are appended, e.g.
+// "Address.Zip".
+func (r fieldRule[T, F]) Validate(value T) error {
+	field := r.get(value)
+	if err := r.rules.Validate(field); err != nil {
+		return fieldError(r.name, field, err)
+	}
+	return nil
+}
+
+// fieldError attributes err, returned by rules for the named field, to that
+// field.
+func fieldError(name string, field any, err error) error {
+	var list ValidationErrors
+	var fe *FieldError
+	if errors.As(err, &list) || errors.As(err, &fe) {
+		return prefixed(name, err)
+	}
+	return NewFieldError(name, field, err)
+}
+
+// Field validates the part of T returned by get with rules that only know
+// about that field's type. The rules for one field stop at the first
+// failure.
+func Field[T, F any](name string, get func(T) F, rules ...Rule[F]) Rule[T] {
+	return fieldRule[T, F]{name: name, get: get, rules: rules}
+}
+
+// Trimmed applies rules to the value with surrounding whitespace removed.
+func Trimmed(rules ...Rule[string]) Rule[string] {
+	return RuleFunc[string](func(value string) error {
+		return RuleSet[string](rules).Validate(strings.TrimSpace(value))
+	})
+}
+
+func Required(err error) Rule[string] {
+	return RuleFunc[string](func(value string) error {
+		if value == "" {
+			return err
+		}
+		return nil
+	})
+}
+
+// MinLength fails with err if value has fewer than n characters (runes).
+func MinLength(n int, err error) Rule[string] {
+	return RuleFunc[string](func(value string) error {
+		if utf8.RuneCountInString(value) < n {
+			return err
+		}
+		return nil
+	})
+}
+
+// MaxLength fails with err if value has more than n characters (runes).
+func MaxLength(n int, err error) Rule[string] {
+	return RuleFunc[string](func(value string) error {
+		if utf8.RuneCountInString(value) > n {
+			return err
+		}
+		return nil
+	})
+}
+
+func Matches(re *regexp.Regexp, err error) Rule[string] {
+	return RuleFunc[string](func(value string) error {
+		if !re.MatchString(value) {
+			return err
+		}
+		return nil
+	})
+}
+
+// Between fails with err if value is outside the inclusive range. The
+// limits are reported as the "min" and "max" parameters.
+func Between(min, max int, err error) Rule[int] {
+	return RuleFunc[int](func(value int) error {
+		if value < min || value > max {
+			return WithParams(err, map[string]any{"min": min, "max": max})
+		}
+		return nil
+	})
+}
+
+// Validator composes per-field and cross-field rules for a type. Unlike a
+// RuleSet it runs every rule and reports all failures together.
+type Validator[T any] struct {
+	rules []Rule[T]
+}
+
+func NewValidator[T any](rules ...Rule[T]) *Validator[T] {
+	return &Validator[T]{rules: rules}
+}
+
+// Add appends rules and returns the validator for chaining.
+func (v *Validator[T]) Add(rules ...Rule[T]) *Validator[T] {
+	v.rules = append(v.rules, rules...)
+	return v
+}
+
+// Validate runs every rule in the order they were added. It returns nil or
+// a ValidationErrors listing each failure.
+func (v *Validator[T]) Validate(value T) error {
+	var errs ValidationErrors
+	for _, rule := range v.rules {
+		if err := rule.Validate(value); err != nil {
+			errs = appendErrors(errs, err)
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
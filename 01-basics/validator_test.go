@@ -0,0 +1,134 @@
+package basics
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+)
+
+var (
+	errTooSmall = errors.New("too small")
+	errOdd      = errors.New("odd")
+)
+
+func TestRuleSetStopsAtFirstError(t *testing.T) {
+	calls := 0
+	counting := RuleFunc[int](func(int) error {
+		calls++
+		return nil
+	})
+
+	rules := RuleSet[int]{Between(10, 20, errTooSmall), counting}
+	if err := rules.Validate(5); !errors.Is(err, errTooSmall) {
+		t.Errorf("error = %v; want errTooSmall", err)
+	}
+	if calls != 0 {
+		t.Errorf("later rule called %d times; want 0", calls)
+	}
+}
+
+func TestWhen(t *testing.T) {
+	even := RuleFunc[int](func(n int) error {
+		if n%2 != 0 {
+			return errOdd
+		}
+		return nil
+	})
+	rule := When(func(n int) bool { return n > 100 }, even)
+
+	tests := []struct {
+		value   int
+		wantErr error
+	}{
+		{3, nil},
+		{101, errOdd},
+		{102, nil},
+	}
+
+	for _, tt := range tests {
+		if err := rule.Validate(tt.value); !errors.Is(err, tt.wantErr) {
+			t.Errorf("Validate(%d) error = %v; want %v", tt.value, err, tt.wantErr)
+		}
+	}
+}
+
+func TestStringRules(t *testing.T) {
+	errRule := errors.New("rule failed")
+	tests := []struct {
+		name    string
+		rule    Rule[string]
+		value   string
+		wantErr error
+	}{
+		{"required empty", Required(errRule), "", errRule},
+		{"required present", Required(errRule), "x", nil},
+		{"min length counts runes", MinLength(3, errRule), "äöü", nil},
+		{"min length too short", MinLength(3, errRule), "ab", errRule},
+		{"max length counts runes", MaxLength(3, errRule), "日本語", nil},
+		{"max length too long", MaxLength(3, errRule), "abcd", errRule},
+		{"matches", Matches(regexp.MustCompile(`^\d+$`), errRule), "123", nil},
+		{"does not match", Matches(regexp.MustCompile(`^\d+$`), errRule), "12a", errRule},
+		{"trimmed", Trimmed(Required(errRule)), "   ", errRule},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.rule.Validate(tt.value); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate(%q) error = %v; want %v", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidatorCrossFieldRule(t *testing.T) {
+	v := DefaultUserValidator().Add(EmailNotContainingUsername)
+
+	valid := User{Username: "johndoe", Email: "jd@example.com", Age: 30}
+	if err := v.Validate(valid); err != nil {
+		t.Errorf("Validate(valid) error = %v; want nil", err)
+	}
+
+	invalid := User{Username: "JohnDoe", Email: "johndoe@example.com", Age: 30}
+	if err := v.Validate(invalid); !errors.Is(err, ErrEmailContainsUsername) {
+		t.Errorf("Validate(invalid) error = %v; want ErrEmailContainsUsername", err)
+	}
+
+	// The default validator used by ValidateUser is unaffected.
+	if err := ValidateUser(invalid); err != nil {
+		t.Errorf("ValidateUser error = %v; want nil", err)
+	}
+}
+
+func TestValidatorCustomRuleSet(t *testing.T) {
+	errAdult := errors.New("must be an adult")
+	adultOnly := NewValidator(
+		Field("Username", func(u User) string { return u.Username }, UsernameRules),
+		Field("Age", func(u User) int { return u.Age }, AgeRules, Between(18, 150, errAdult)),
+		When(func(u User) bool { return u.Email != "" },
+			Field("Email", func(u User) string { return u.Email }, EmailRules)),
+	)
+
+	tests := []struct {
+		name    string
+		user    User
+		wantErr error
+	}{
+		{"adult without email", User{Username: "alice", Age: 30}, nil},
+		{"minor", User{Username: "bob", Age: 12}, errAdult},
+		{"invalid age reports age rules first", User{Username: "bob", Age: -1}, ErrInvalidAge},
+		{"email checked when present", User{Username: "carol", Email: "nope", Age: 40}, ErrInvalidEmail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := adultOnly.Validate(tt.user); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate(%+v) error = %v; want %v", tt.user, err, tt.wantErr)
+			}
+		})
+	}
+
+	err := adultOnly.Validate(User{Username: "", Age: 12})
+	if !errors.Is(err, ErrEmptyUsername) || !errors.Is(err, errAdult) {
+		t.Errorf("Validate error = %v; want both ErrEmptyUsername and errAdult", err)
+	}
+}